@@ -0,0 +1,424 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Kategoriyalar alohida saqlanmaydi: yozuv nomi "Alucard / Laning" ko'rinishida
+// yo'l bo'lsa, "Alucard" rol ichidagi kategoriya, "Laning" esa yozuvning o'zi.
+// Shu tariqa ichma-ich kategoriyalar istalgan chuqurlikda bo'lishi mumkin.
+// Ajratuvchi faqat ikki tomonida bo'shliq bo'lgan "/" - "AC/DC" yoki
+// "Marksman/ADK" kabi nomlar bitta nom bo'lib qoladi.
+const categorySeparator = " / "
+
+// Kategoriya tugmalari yozuv tugmalaridan shu belgi bilan ajraladi
+const categoryButtonPrefix = "📁 "
+
+// Yozuv nomini kategoriyalar yo'li va oxirgi nomga ajratish
+func splitEntryPath(title string) ([]string, string) {
+	var parts []string
+	for _, part := range strings.Split(title, categorySeparator) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, title
+	}
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// Yo'l qismlarini yozuv nomiga birlashtirish
+func joinEntryPath(parts ...string) string {
+	return strings.Join(parts, categorySeparator)
+}
+
+// TempData dagi joriy kategoriya yo'li
+func currentPath(state *UserState) []string {
+	path, name := splitEntryPath(state.TempData["path"])
+	if name == "" {
+		return nil
+	}
+	return append(path, name)
+}
+
+// Yo'l boshqa yo'lning boshlanishi ekanligini tekshirish
+func hasPathPrefix(path, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Kategoriya darajasidagi ichki kategoriyalar va yozuvlar (tugma matni -> yozuv nomi)
+func categoryLevel(entries map[string]Entry, role string, path []string) ([]string, map[string]string) {
+	folderSet := make(map[string]bool)
+	items := make(map[string]string)
+	for title, entry := range entries {
+		if !entry.HasRole(role) {
+			continue
+		}
+		entryPath, name := splitEntryPath(title)
+		if !hasPathPrefix(entryPath, path) {
+			continue
+		}
+		if len(entryPath) > len(path) {
+			folderSet[entryPath[len(path)]] = true
+		} else {
+			items[name] = title
+		}
+	}
+
+	folders := make([]string, 0, len(folderSet))
+	for folder := range folderSet {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+	return folders, items
+}
+
+// Navigatsiya yo'li: "📚 Tutorials › Fighter › Alucard"
+func breadcrumb(section Section, role string, path []string) string {
+	parts := []string{section.Button()}
+	if role != "" {
+		parts = append(parts, role)
+	}
+	parts = append(parts, path...)
+	return strings.Join(parts, " › ")
+}
+
+// Rol ichidagi kategoriya darajasini ko'rsatish (path bo'sh bo'lsa - rolning o'zi)
+func showSectionRole(ctx *Context, section Section, role string, path []string) {
+	setRoleListing(ctx.State, section, role, path)
+	showListing(ctx, 0, editInPlace(ctx))
+}
+
+// Tanlangan rol va kategoriyani saqlash - orqaga qaytish va yozuv yaratishda kerak
+func setRoleListing(state *UserState, section Section, role string, path []string) {
+	state.TempData["section"] = section.ID
+	state.TempData["selectedRole"] = role
+	state.TempData["path"] = joinEntryPath(path...)
+	delete(state.TempData, "tags")
+	delete(state.TempData, "tag")
+}
+
+// browseItem - ro'yxatdagi kategoriya yoki yozuv
+type browseItem struct {
+	label  string // tugma matni
+	folder string // kategoriya bo'lsa uning nomi
+	title  string // yozuv bo'lsa uning to'liq nomi
+	// Tugma callbacki: yozuv uchun uning ID si, kategoriya uchun ichidagi
+	// biror yozuv ID si va kategoriya chuqurligi ("id:chuqurlik"). Ro'yxat
+	// o'zgarsa ham eski xabardagi tugma o'sha yozuv yoki kategoriyani ochadi.
+	key string
+}
+
+// Kategoriya ichidagi birinchi (nom bo'yicha) yozuv ID si va kategoriya chuqurligi
+func folderKey(entries map[string]Entry, role string, folderPath []string) string {
+	for _, title := range sortedTitles(entries) {
+		entryPath, _ := splitEntryPath(title)
+		if entries[title].HasRole(role) && len(entryPath) >= len(folderPath) && hasPathPrefix(entryPath, folderPath) {
+			return fmt.Sprintf("%s:%d", entries[title].ID, len(folderPath))
+		}
+	}
+	return ""
+}
+
+// Foydalanuvchi ko'rayotgan ro'yxat: teg tanlangan bo'lsa shu tegdagi yozuvlar,
+// aks holda rol ichidagi kategoriya darajasi (qadalgan yozuvlar, kategoriyalar,
+// keyin qolgan yozuvlar - entryLess tartibida)
+func currentListing(state *UserState) (Section, string, []browseItem, bool, error) {
+	section, exists, err := store.Section(state.TempData["section"])
+	if err != nil || !exists {
+		return Section{}, "", nil, false, err
+	}
+	entries, err := store.Entries(section.ID)
+	if err != nil {
+		return Section{}, "", nil, false, err
+	}
+
+	var items []browseItem
+	if tag := state.TempData["tag"]; tag != "" {
+		for _, title := range sortedTitles(entries) {
+			if entries[title].HasTag(tag) {
+				items = append(items, browseItem{label: entryLabel(title, entries[title]), title: title, key: entries[title].ID})
+			}
+		}
+		return section, fmt.Sprintf("%s › %s%s", section.Button(), tagButtonPrefix, tag), items, true, nil
+	}
+
+	role := state.TempData["selectedRole"]
+	if role == "" {
+		return Section{}, "", nil, false, nil
+	}
+	path := currentPath(state)
+	folders, levelItems := categoryLevel(entries, role, path)
+	names := make([]string, 0, len(levelItems))
+	for name := range levelItems {
+		names = append(names, name)
+	}
+	sortEntryNames(names, levelItems, entries)
+
+	// Qadalgan yozuvlar kategoriyalardan ham yuqorida turadi
+	pinned := 0
+	for pinned < len(names) && entries[levelItems[names[pinned]]].Pinned {
+		pinned++
+	}
+	for _, name := range names[:pinned] {
+		items = append(items, browseItem{label: entryLabel(name, entries[levelItems[name]]), title: levelItems[name], key: entries[levelItems[name]].ID})
+	}
+	for _, folder := range folders {
+		items = append(items, browseItem{label: categoryButtonPrefix + folder, folder: folder, key: folderKey(entries, role, append(path[:len(path):len(path)], folder))})
+	}
+	for _, name := range names[pinned:] {
+		items = append(items, browseItem{label: entryLabel(name, entries[levelItems[name]]), title: levelItems[name], key: entries[levelItems[name]].ID})
+	}
+	return section, breadcrumb(section, role, path), items, true, nil
+}
+
+// Joriy ro'yxatning bitta sahifasini inline tugmalar bilan ko'rsatish.
+// edit bo'lsa, callback kelgan xabar joyida yangilanadi.
+func showListing(ctx *Context, page int, edit bool) {
+	section, heading, items, ok, err := currentListing(ctx.State)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !ok {
+		showTopMenu(ctx)
+		return
+	}
+
+	role, tag := ctx.State.TempData["selectedRole"], ctx.State.TempData["tag"]
+	text := heading + ":"
+	if len(items) == 0 {
+		switch {
+		case tag != "":
+			text = fmt.Sprintf("'%s%s' tegiga ega yozuvlar topilmadi.", tagButtonPrefix, tag)
+		case ctx.State.TempData["path"] != "":
+			text = fmt.Sprintf("%s kategoriyasida yozuvlar mavjud emas.", heading)
+		default:
+			text = fmt.Sprintf("Hozircha %s bo'limida '%s' roli uchun yozuvlar mavjud emas.", section.Button(), role)
+		}
+	}
+
+	start, end, page, pages := pageBounds(len(items), page, userPageSize)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i := start; i < end; i++ {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(items[i].label, "browse_open:"+items[i].key),
+		))
+	}
+	if pager := pagerRow(listingPagePrefix(ctx.State, section, items, pages), page, pages); pager != nil {
+		rows = append(rows, pager)
+	}
+
+	// Admin uchun yangi yozuv qo'shishni taklif qilish
+	if tag == "" && isAdmin(ctx.User.UserName) {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(newEntryHereButton, "new_entry_here"),
+		))
+	}
+	if inlineNavigation() {
+		rows = append(rows, inlineBackRow(listingBackTarget(ctx.State, section, items)))
+	}
+
+	sendOrEdit(ctx, edit, text, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows})
+}
+
+// Ro'yxatdan qaytish joyi: teg yozuvlaridan - teglar, rolning o'zidan - bo'lim,
+// kategoriyadan - bir daraja yuqorisi (ro'yxatdagi istalgan yozuv yo'li orqali)
+func listingBackTarget(state *UserState, section Section, items []browseItem) string {
+	path := currentPath(state)
+	switch {
+	case state.TempData["tag"] != "":
+		return "t:" + section.ID
+	case len(path) == 0 || len(items) == 0:
+		return "s:" + section.ID
+	}
+	id, _, _ := strings.Cut(items[0].key, ":")
+	return fmt.Sprintf("f:%s:%d", id, len(path)-1)
+}
+
+// Sahifa tugmalari prefiksi: "browse_page:ro'yxat:" (nav_back dagi kabi
+// g:bo'lim:teg yoki f:yozuv:chuqurlik), shunda eski xabardagi tugma holat
+// o'zgargan bo'lsa ham o'z ro'yxatini varaqlaydi. 64 baytga sig'masa -
+// faqat sahifa raqami.
+func listingPagePrefix(state *UserState, section Section, items []browseItem, pages int) string {
+	var target string
+	switch {
+	case state.TempData["tag"] != "":
+		target = fmt.Sprintf("g:%s:%s", section.ID, state.TempData["tag"])
+	case len(items) > 0:
+		id, _, _ := strings.Cut(items[0].key, ":")
+		target = fmt.Sprintf("f:%s:%d", id, len(currentPath(state)))
+	}
+	prefix := "browse_page:" + target + ":"
+	if target == "" || len(prefix)+len(strconv.Itoa(pages)) > callbackDataLimit {
+		return "browse_page:"
+	}
+	return prefix
+}
+
+// Ro'yxat sahifasini almashtirish ("browse_page:ro'yxat:sahifa" yoki "browse_page:sahifa")
+func handleBrowsePageCallback(ctx *Context) {
+	page := ctx.Arg
+	if i := strings.LastIndex(ctx.Arg, ":"); i >= 0 {
+		page = ctx.Arg[i+1:]
+		kind, rest, _ := strings.Cut(ctx.Arg[:i], ":")
+		switch kind {
+		case "g":
+			sectionID, tag, _ := strings.Cut(rest, ":")
+			section, exists, err := store.Section(sectionID)
+			if err != nil {
+				reportStoreError(ctx.Bot, ctx.ChatID, err)
+				return
+			}
+			if exists {
+				setTagListing(ctx.State, section, tag)
+			}
+		case "f":
+			id, depth, _ := strings.Cut(rest, ":")
+			section, role, path, ok, err := entryFolder(ctx.State, id, depth)
+			if err != nil {
+				reportStoreError(ctx.Bot, ctx.ChatID, err)
+				return
+			}
+			if ok {
+				setRoleListing(ctx.State, section, role, path)
+			}
+		}
+	}
+	showListing(ctx, callbackNumber(page), true)
+}
+
+// Ro'yxatdagi kategoriya yoki yozuvni ochish ("browse_open:id" yoki "browse_open:id:chuqurlik")
+func handleBrowseOpenCallback(ctx *Context) {
+	id, depth, isFolder := strings.Cut(ctx.Arg, ":")
+	if isFolder {
+		showEntryFolder(ctx, id, depth)
+		return
+	}
+	sectionID, title, found, err := store.FindEntry(id)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !found {
+		// Yozuv o'chirilgan - yangilangan ro'yxatni ko'rsatamiz
+		showListing(ctx, 0, true)
+		return
+	}
+	section, exists, err := store.Section(sectionID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !exists {
+		showTopMenu(ctx)
+		return
+	}
+
+	logUserAction(ctx.User, "Yozuv ko'rildi", entryLogRef(section.ID, title))
+	showEntry(ctx, section, title)
+}
+
+// Yozuv yo'lidagi depth chuqurlikdagi kategoriyani ochish (0 - rolning o'zi)
+func showEntryFolder(ctx *Context, id, depth string) {
+	section, role, path, ok, err := entryFolder(ctx.State, id, depth)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !ok {
+		showListing(ctx, 0, true)
+		return
+	}
+	if ctx.State.State == STATE_ENTRY_SELECTED {
+		ctx.State.State = STATE_NONE
+		delete(ctx.State.TempData, "selectedTitle")
+	}
+	if len(path) > 0 {
+		logUserAction(ctx.User, "Kategoriyaga kirdi", breadcrumb(section, role, path))
+	}
+	showSectionRole(ctx, section, role, path)
+}
+
+// Yozuv ID si va chuqurlik bo'yicha kategoriya: bo'lim, rol va yo'l. Rol joriy
+// holatdan olinadi, yozuv unda bo'lmasa - yozuvning birinchi roli. Yozuv
+// yoki bo'lim topilmasa yoki chuqurlik noto'g'ri bo'lsa, ok false bo'ladi.
+func entryFolder(state *UserState, id, depth string) (Section, string, []string, bool, error) {
+	sectionID, title, found, err := store.FindEntry(id)
+	if err != nil || !found {
+		return Section{}, "", nil, false, err
+	}
+	section, exists, err := store.Section(sectionID)
+	if err != nil || !exists {
+		return Section{}, "", nil, false, err
+	}
+	entry, _, err := store.Entry(sectionID, title)
+	if err != nil {
+		return Section{}, "", nil, false, err
+	}
+	entryPath, _ := splitEntryPath(title)
+	n, convErr := strconv.Atoi(depth)
+	if convErr != nil || n < 0 || n > len(entryPath) || len(entry.Roles) == 0 {
+		return Section{}, "", nil, false, nil
+	}
+	role := state.TempData["selectedRole"]
+	if !entry.HasRole(role) {
+		role = entry.Roles[0]
+	}
+	return section, role, entryPath[:n], true, nil
+}
+
+// Joriy kategoriya darajasidan kategoriya yoki yozuv tanlash.
+// Tanlov bajarilgan bo'lsa true qaytaradi.
+func selectInCategory(ctx *Context) bool {
+	role := ctx.State.TempData["selectedRole"]
+	if role == "" {
+		return false
+	}
+	section, exists, err := store.Section(ctx.State.TempData["section"])
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return true
+	}
+	if !exists {
+		return false
+	}
+	entries, err := store.Entries(section.ID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return true
+	}
+
+	path := currentPath(ctx.State)
+	folders, items := categoryLevel(entries, role, path)
+	if folder, ok := strings.CutPrefix(ctx.Text, categoryButtonPrefix); ok {
+		for _, name := range folders {
+			if name == folder {
+				logUserAction(ctx.User, "Kategoriyaga kirdi", breadcrumb(section, role, append(path, folder)))
+				showSectionRole(ctx, section, role, append(path, folder))
+				return true
+			}
+		}
+		return false
+	}
+	if title, ok := items[ctx.Text]; ok {
+		logUserAction(ctx.User, "Yozuv ko'rildi", entryLogRef(section.ID, title))
+		showEntry(ctx, section, title)
+		return true
+	}
+	return false
+}
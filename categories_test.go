@@ -0,0 +1,159 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitEntryPath(t *testing.T) {
+	tests := []struct {
+		title    string
+		wantPath []string
+		wantName string
+	}{
+		{"Chichi", []string{}, "Chichi"},
+		{"Alucard / Laning", []string{"Alucard"}, "Laning"},
+		{"Fighter / Alucard / Laning", []string{"Fighter", "Alucard"}, "Laning"},
+		{"AC/DC", []string{}, "AC/DC"},
+		{"Marksman/ADK / Layla", []string{"Marksman/ADK"}, "Layla"},
+		{" / Layla / ", []string{}, "Layla"},
+		{"", nil, ""},
+	}
+	for _, tt := range tests {
+		path, name := splitEntryPath(tt.title)
+		if !reflect.DeepEqual(path, tt.wantPath) || name != tt.wantName {
+			t.Errorf("splitEntryPath(%q) = (%q, %q), want (%q, %q)", tt.title, path, name, tt.wantPath, tt.wantName)
+		}
+	}
+}
+
+func TestValidateTitle(t *testing.T) {
+	tests := []struct {
+		value, want string
+		valid       bool
+	}{
+		{"Chichi", "Chichi", true},
+		{"Alucard   /  Laning", "Alucard / Laning", true},
+		{"AC/DC", "AC/DC", true},
+		{"/start", "", false},
+		{"📁 Papka / Chichi", "", false},
+		{"Alucard / 📁 Laning", "", false},
+	}
+	for _, tt := range tests {
+		got, err := validateTitle(tt.value)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("validateTitle(%q) = (%q, %v), want %q (valid %v)", tt.value, got, err, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestCategoryLevel(t *testing.T) {
+	entries := map[string]Entry{
+		"Alucard / Laning":         {Roles: []string{"Fighter"}},
+		"Alucard / Jungle / Early": {Roles: []string{"Fighter"}},
+		"Chichi":                   {Roles: []string{"Fighter", "Tank"}},
+		"Zed / Combo":              {Roles: []string{"Assassin"}},
+		"AC/DC":                    {Roles: []string{"Fighter"}},
+	}
+	tests := []struct {
+		name        string
+		role        string
+		path        []string
+		wantFolders []string
+		wantItems   map[string]string
+	}{
+		{
+			name:        "rol darajasi",
+			role:        "Fighter",
+			wantFolders: []string{"Alucard"},
+			wantItems:   map[string]string{"Chichi": "Chichi", "AC/DC": "AC/DC"},
+		},
+		{
+			name:        "kategoriya ichida",
+			role:        "Fighter",
+			path:        []string{"Alucard"},
+			wantFolders: []string{"Jungle"},
+			wantItems:   map[string]string{"Laning": "Alucard / Laning"},
+		},
+		{
+			name:        "ichki kategoriya",
+			role:        "Fighter",
+			path:        []string{"Alucard", "Jungle"},
+			wantFolders: []string{},
+			wantItems:   map[string]string{"Early": "Alucard / Jungle / Early"},
+		},
+		{
+			name:        "boshqa rol",
+			role:        "Tank",
+			wantFolders: []string{},
+			wantItems:   map[string]string{"Chichi": "Chichi"},
+		},
+		{
+			name:        "mavjud bo'lmagan kategoriya",
+			role:        "Fighter",
+			path:        []string{"Zed"},
+			wantFolders: []string{},
+			wantItems:   map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		folders, items := categoryLevel(entries, tt.role, tt.path)
+		if !reflect.DeepEqual(folders, tt.wantFolders) || !reflect.DeepEqual(items, tt.wantItems) {
+			t.Errorf("%s: categoryLevel = (%v, %v), want (%v, %v)", tt.name, folders, items, tt.wantFolders, tt.wantItems)
+		}
+	}
+}
+
+func TestBreadcrumb(t *testing.T) {
+	section := Section{ID: "tutorials", Name: "Tutorials", Emoji: "📚"}
+	tests := []struct {
+		role string
+		path []string
+		want string
+	}{
+		{"", nil, "📚 Tutorials"},
+		{"Fighter", nil, "📚 Tutorials › Fighter"},
+		{"Fighter", []string{"Alucard", "Jungle"}, "📚 Tutorials › Fighter › Alucard › Jungle"},
+	}
+	for _, tt := range tests {
+		if got := breadcrumb(section, tt.role, tt.path); got != tt.want {
+			t.Errorf("breadcrumb(%q, %v) = %q, want %q", tt.role, tt.path, got, tt.want)
+		}
+	}
+}
+
+// Ro'yxat tugmalari yozuv ID si bilan ishlaydi: ro'yxat o'zgargandan keyin
+// ham eski xabardagi tugma o'sha yozuv yoki kategoriyani ochadi
+func TestBrowseOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		click    string
+		wantText []string
+	}{
+		{"yozuv", "browse_open:aaaa0003", []string{"Zor geroy"}},
+		{"kategoriya", "browse_open:aaaa0001:1", []string{"Fighter › Alucard", "Laning", "Jungle"}},
+		{"rol darajasi", "browse_open:aaaa0001:0", []string{"📁 Alucard", "Chichi"}},
+		{"o'chirilgan yozuv", "browse_open:ffffffff", []string{"📁 Alucard"}},
+		{"noto'g'ri chuqurlik", "browse_open:aaaa0001:5", []string{"📁 Alucard"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, "guest")
+			store.SaveEntry("tutorials", "Alucard / Laning", testEntry("aaaa0001", "Fighter"))
+			store.SaveEntry("tutorials", "Alucard / Jungle", testEntry("aaaa0002", "Fighter"))
+			store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0003", "Fighter"))
+			state := getUserState(c.user.ID)
+			state.TempData["section"] = "tutorials"
+			state.TempData["selectedRole"] = "Fighter"
+
+			out := replies(c.click(tt.click))
+			for _, want := range tt.wantText {
+				if !strings.Contains(out, want) {
+					t.Errorf("javobda %q yo'q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config - botning barcha sozlamalari.
+// Ustuvorlik tartibi (pastdagisi yuqoridagisini bekor qiladi):
+// standart qiymatlar -> YAML fayl -> muhit o'zgaruvchilari (BOT_*) -> buyruq qatori flaglari.
+type Config struct {
+	BotToken       string        `yaml:"bot_token"`
+	AdminUsername  string        `yaml:"admin_username"`  // @ belgisisiz
+	PrivateChannel string        `yaml:"private_channel"` // -100 bilan boshlanadi
+	FileIDChat     string        `yaml:"file_id_chat"`    // video fayl ID larini aniqlash uchun chat (ixtiyoriy)
+	DataFile       string        `yaml:"data_file"`
+	LogsDir        string        `yaml:"logs_dir"`
+	Storage        string        `yaml:"storage"` // json yoki sqlite
+	SQLiteFile     string        `yaml:"sqlite_file"`
+	UserStateTTL   time.Duration `yaml:"user_state_ttl"`   // tugallanmagan jarayonlar shu vaqtdan keyin bekor qilinadi
+	Workers        int           `yaml:"workers"`          // yangilanishlarni parallel qayta ishlovchi ishchilar soni
+	SendGlobalRate float64       `yaml:"send_global_rate"` // sekundiga jami yuboriladigan xabarlar
+	SendChatRate   float64       `yaml:"send_chat_rate"`   // sekundiga bitta chatga yuboriladigan xabarlar
+	SendMaxRetries int           `yaml:"send_max_retries"` // 429 va vaqtinchalik xatoliklarda qayta urinishlar
+	UserRateLimit  float64       `yaml:"user_rate_limit"`  // bitta foydalanuvchidan sekundiga qabul qilinadigan yangilanishlar
+	Navigation     string        `yaml:"navigation"`       // reply yoki inline
+	NewBadgeWindow time.Duration `yaml:"new_badge_window"` // shu vaqt ichida yaratilgan/yangilangan yozuvlar 🆕 bilan belgilanadi (0 - o'chirilgan)
+
+	// Faqat buyruq qatoridan
+	ConfigFile    string `yaml:"-"`
+	MigrateDryRun bool   `yaml:"-"`
+}
+
+// Standart sozlamalar
+func defaultConfig() Config {
+	return Config{
+		DataFile:       "tutorial_data.json",
+		LogsDir:        "user_logs",
+		Storage:        "json",
+		SQLiteFile:     "bot.db",
+		UserStateTTL:   time.Hour,
+		Workers:        8,
+		SendGlobalRate: 25,
+		SendChatRate:   1,
+		SendMaxRetries: 3,
+		UserRateLimit:  2,
+		Navigation:     navigationReply,
+		NewBadgeWindow: 72 * time.Hour,
+	}
+}
+
+// Sozlamalarni yuklash: standart -> fayl -> muhit -> flaglar
+func loadConfig(args []string) (Config, error) {
+	cfg := defaultConfig()
+
+	fs := flag.NewFlagSet("bot", flag.ContinueOnError)
+	configFile := fs.String("config", "", "YAML sozlamalar fayli (yoki BOT_CONFIG)")
+	token := fs.String("token", "", "Telegram bot tokeni")
+	admin := fs.String("admin", "", "Asosiy admin username'i")
+	channel := fs.String("channel", "", "Videolar saqlanadigan private kanal ID'si")
+	fileIDChat := fs.String("file-id-chat", "", "Video fayl ID larini aniqlash uchun chat ID'si (ixtiyoriy)")
+	dataFileFlag := fs.String("data-file", "", "JSON ma'lumotlar fayli")
+	logsDirFlag := fs.String("logs-dir", "", "Harakatlar jurnali papkasi")
+	storage := fs.String("storage", "", "Ma'lumotlar ombori: json yoki sqlite")
+	sqliteFileFlag := fs.String("sqlite-file", "", "SQLite bazasi fayli")
+	stateTTL := fs.Duration("user-state-ttl", 0, "Tugallanmagan jarayonlarning amal qilish muddati")
+	workers := fs.Int("workers", 0, "Yangilanishlarni qayta ishlovchi ishchilar soni")
+	globalRate := fs.Float64("send-global-rate", 0, "Sekundiga jami yuboriladigan xabarlar soni")
+	chatRate := fs.Float64("send-chat-rate", 0, "Sekundiga bitta chatga yuboriladigan xabarlar soni")
+	maxRetries := fs.Int("send-max-retries", 0, "Yuborishda qayta urinishlar soni")
+	userRate := fs.Float64("user-rate-limit", 0, "Bitta foydalanuvchidan sekundiga qabul qilinadigan yangilanishlar")
+	navigation := fs.String("navigation", "", "Navigatsiya rejimi: reply yoki inline")
+	badgeWindow := fs.Duration("new-badge-window", 0, "Yozuv shu vaqt davomida 🆕 belgisi bilan ko'rsatiladi (0 - o'chirilgan)")
+	fs.BoolVar(&cfg.MigrateDryRun, "migrate-dry-run", false, "Migratsiyalarni bajarmasdan, nima o'zgarishini ko'rsatish")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	// 1. Sozlamalar fayli
+	cfg.ConfigFile = firstNonEmpty(*configFile, os.Getenv("BOT_CONFIG"))
+	if cfg.ConfigFile != "" {
+		fileData, err := os.ReadFile(cfg.ConfigFile)
+		if err != nil {
+			return cfg, fmt.Errorf("sozlamalar faylini o'qishda xatolik: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(fileData))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return cfg, fmt.Errorf("%s faylini o'qishda xatolik: %w", cfg.ConfigFile, err)
+		}
+	}
+
+	// 2. Muhit o'zgaruvchilari
+	envStrings := map[string]*string{
+		"BOT_TOKEN":           &cfg.BotToken,
+		"BOT_ADMIN_USERNAME":  &cfg.AdminUsername,
+		"BOT_PRIVATE_CHANNEL": &cfg.PrivateChannel,
+		"BOT_FILE_ID_CHAT":    &cfg.FileIDChat,
+		"BOT_DATA_FILE":       &cfg.DataFile,
+		"BOT_LOGS_DIR":        &cfg.LogsDir,
+		"BOT_STORAGE":         &cfg.Storage,
+		"BOT_SQLITE_FILE":     &cfg.SQLiteFile,
+		"BOT_NAVIGATION":      &cfg.Navigation,
+	}
+	for name, target := range envStrings {
+		if value, exists := os.LookupEnv(name); exists {
+			*target = value
+		}
+	}
+	if value, exists := os.LookupEnv("BOT_USER_STATE_TTL"); exists {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return cfg, fmt.Errorf("BOT_USER_STATE_TTL noto'g'ri: %w", err)
+		}
+		cfg.UserStateTTL = ttl
+	}
+	if value, exists := os.LookupEnv("BOT_NEW_BADGE_WINDOW"); exists {
+		window, err := time.ParseDuration(value)
+		if err != nil {
+			return cfg, fmt.Errorf("BOT_NEW_BADGE_WINDOW noto'g'ri: %w", err)
+		}
+		cfg.NewBadgeWindow = window
+	}
+	envInts := map[string]*int{
+		"BOT_WORKERS":          &cfg.Workers,
+		"BOT_SEND_MAX_RETRIES": &cfg.SendMaxRetries,
+	}
+	for name, target := range envInts {
+		if value, exists := os.LookupEnv(name); exists {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return cfg, fmt.Errorf("%s noto'g'ri: %w", name, err)
+			}
+			*target = number
+		}
+	}
+	envFloats := map[string]*float64{
+		"BOT_SEND_GLOBAL_RATE": &cfg.SendGlobalRate,
+		"BOT_SEND_CHAT_RATE":   &cfg.SendChatRate,
+		"BOT_USER_RATE_LIMIT":  &cfg.UserRateLimit,
+	}
+	for name, target := range envFloats {
+		if value, exists := os.LookupEnv(name); exists {
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return cfg, fmt.Errorf("%s noto'g'ri: %w", name, err)
+			}
+			*target = number
+		}
+	}
+
+	// 3. Faqat aniq berilgan flaglar
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "token":
+			cfg.BotToken = *token
+		case "admin":
+			cfg.AdminUsername = *admin
+		case "channel":
+			cfg.PrivateChannel = *channel
+		case "file-id-chat":
+			cfg.FileIDChat = *fileIDChat
+		case "data-file":
+			cfg.DataFile = *dataFileFlag
+		case "logs-dir":
+			cfg.LogsDir = *logsDirFlag
+		case "storage":
+			cfg.Storage = *storage
+		case "sqlite-file":
+			cfg.SQLiteFile = *sqliteFileFlag
+		case "user-state-ttl":
+			cfg.UserStateTTL = *stateTTL
+		case "workers":
+			cfg.Workers = *workers
+		case "send-global-rate":
+			cfg.SendGlobalRate = *globalRate
+		case "send-chat-rate":
+			cfg.SendChatRate = *chatRate
+		case "send-max-retries":
+			cfg.SendMaxRetries = *maxRetries
+		case "user-rate-limit":
+			cfg.UserRateLimit = *userRate
+		case "navigation":
+			cfg.Navigation = *navigation
+		case "new-badge-window":
+			cfg.NewBadgeWindow = *badgeWindow
+		}
+	})
+
+	cfg.AdminUsername = strings.TrimPrefix(strings.TrimSpace(cfg.AdminUsername), "@")
+	cfg.BotToken = strings.TrimSpace(cfg.BotToken)
+	cfg.PrivateChannel = strings.TrimSpace(cfg.PrivateChannel)
+	cfg.FileIDChat = strings.TrimSpace(cfg.FileIDChat)
+	cfg.Navigation = strings.ToLower(strings.TrimSpace(cfg.Navigation))
+	return cfg, nil
+}
+
+var botTokenPattern = regexp.MustCompile(`^\d+:[A-Za-z0-9_-]{30,}$`)
+
+// Sozlamalarni tekshirish. Barcha xatoliklar birdaniga qaytariladi.
+func (c Config) validate() error {
+	var problems []string
+
+	// Migratsiyani tekshirish uchun token va kanal kerak emas
+	if !c.MigrateDryRun {
+		if c.BotToken == "" {
+			problems = append(problems, "bot tokeni berilmagan (BOT_TOKEN, -token yoki bot_token)")
+		} else if !botTokenPattern.MatchString(c.BotToken) {
+			problems = append(problems, "bot tokeni noto'g'ri formatda")
+		}
+		if c.AdminUsername == "" {
+			problems = append(problems, "asosiy admin berilmagan (BOT_ADMIN_USERNAME, -admin yoki admin_username)")
+		}
+		if !strings.HasPrefix(c.PrivateChannel, "-100") {
+			problems = append(problems, "private kanal ID'si -100 bilan boshlanishi kerak (BOT_PRIVATE_CHANNEL, -channel yoki private_channel)")
+		} else if _, err := strconv.ParseInt(c.PrivateChannel, 10, 64); err != nil {
+			problems = append(problems, "private kanal ID'si raqam bo'lishi kerak")
+		}
+	}
+	if c.FileIDChat != "" {
+		if _, err := strconv.ParseInt(c.FileIDChat, 10, 64); err != nil {
+			problems = append(problems, "file_id_chat raqam bo'lishi kerak (chat ID'si)")
+		}
+	}
+	if c.Storage != "json" && c.Storage != "sqlite" {
+		problems = append(problems, fmt.Sprintf("noma'lum ombor turi %q (json yoki sqlite)", c.Storage))
+	}
+	if c.Navigation != navigationReply && c.Navigation != navigationInline {
+		problems = append(problems, fmt.Sprintf("noma'lum navigatsiya rejimi %q (reply yoki inline)", c.Navigation))
+	}
+	if c.DataFile == "" {
+		problems = append(problems, "ma'lumotlar fayli ko'rsatilmagan")
+	}
+	if c.Storage == "sqlite" && c.SQLiteFile == "" {
+		problems = append(problems, "SQLite bazasi fayli ko'rsatilmagan")
+	}
+	if c.LogsDir == "" {
+		problems = append(problems, "jurnal papkasi ko'rsatilmagan")
+	}
+	if c.UserStateTTL <= 0 {
+		problems = append(problems, "user_state_ttl musbat bo'lishi kerak")
+	}
+	if c.NewBadgeWindow < 0 {
+		problems = append(problems, "new_badge_window manfiy bo'lishi mumkin emas")
+	}
+	if c.Workers < 1 {
+		problems = append(problems, "workers kamida 1 bo'lishi kerak")
+	}
+	if c.SendGlobalRate <= 0 || c.SendChatRate <= 0 {
+		problems = append(problems, "send_global_rate va send_chat_rate musbat bo'lishi kerak")
+	}
+	if c.UserRateLimit <= 0 {
+		problems = append(problems, "user_rate_limit musbat bo'lishi kerak")
+	}
+	if c.SendMaxRetries < 0 {
+		problems = append(problems, "send_max_retries manfiy bo'lishi mumkin emas")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("sozlamalarda xatolik:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
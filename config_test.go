@@ -0,0 +1,258 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testToken = "123456:ABCDEFGHIJKLMNOPQRSTUVWXYZabcdef_-"
+
+// Testdan oldin muhitdagi BOT_* o'zgaruvchilarini olib tashlash (test tugagach tiklanadi)
+func clearBotEnv(t *testing.T) {
+	t.Helper()
+	for _, pair := range os.Environ() {
+		name, _, _ := strings.Cut(pair, "=")
+		if strings.HasPrefix(name, "BOT_") {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	file := `admin_username: "@file_admin"
+private_channel: "-1001"
+data_file: "file.json"
+storage: "sqlite"
+workers: 3
+user_state_ttl: "30m"
+navigation: "Inline"
+`
+	tests := []struct {
+		name  string
+		env   map[string]string
+		args  []string
+		check func(t *testing.T, cfg Config)
+	}{
+		{
+			name: "standart qiymatlar",
+			check: func(t *testing.T, cfg Config) {
+				if cfg != defaultConfig() {
+					t.Errorf("cfg = %+v, want %+v", cfg, defaultConfig())
+				}
+			},
+		},
+		{
+			name: "fayl standart qiymatlarni bekor qiladi",
+			args: []string{"-config", "$FILE"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.AdminUsername != "file_admin" || cfg.PrivateChannel != "-1001" || cfg.DataFile != "file.json" {
+					t.Errorf("fayl qiymatlari o'qilmadi: %+v", cfg)
+				}
+				if cfg.Storage != "sqlite" || cfg.Workers != 3 || cfg.UserStateTTL != 30*time.Minute {
+					t.Errorf("fayl qiymatlari o'qilmadi: %+v", cfg)
+				}
+				if cfg.Navigation != navigationInline {
+					t.Errorf("Navigation = %q, want %q", cfg.Navigation, navigationInline)
+				}
+				if cfg.SQLiteFile != "bot.db" || cfg.SendGlobalRate != 25 {
+					t.Errorf("faylda yo'q qiymatlar standartda qolishi kerak: %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "BOT_CONFIG orqali fayl",
+			env:  map[string]string{"BOT_CONFIG": "$FILE"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.DataFile != "file.json" {
+					t.Errorf("DataFile = %q, want file.json", cfg.DataFile)
+				}
+			},
+		},
+		{
+			name: "muhit faylni bekor qiladi",
+			env: map[string]string{
+				"BOT_DATA_FILE":        "/data/env.json",
+				"BOT_SQLITE_FILE":      "/data/env.db",
+				"BOT_WORKERS":          "5",
+				"BOT_SEND_GLOBAL_RATE": "10.5",
+				"BOT_USER_STATE_TTL":   "2h",
+				"BOT_NEW_BADGE_WINDOW": "0s",
+			},
+			args: []string{"-config", "$FILE"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.DataFile != "/data/env.json" || cfg.SQLiteFile != "/data/env.db" {
+					t.Errorf("fayl yo'llari muhitdan olinmadi: %+v", cfg)
+				}
+				if cfg.Workers != 5 || cfg.SendGlobalRate != 10.5 || cfg.UserStateTTL != 2*time.Hour || cfg.NewBadgeWindow != 0 {
+					t.Errorf("muhit qiymatlari o'qilmadi: %+v", cfg)
+				}
+				if cfg.AdminUsername != "file_admin" {
+					t.Errorf("muhitda yo'q qiymat fayldan qolishi kerak: %q", cfg.AdminUsername)
+				}
+			},
+		},
+		{
+			name: "flaglar muhitni bekor qiladi",
+			env:  map[string]string{"BOT_WORKERS": "5", "BOT_STORAGE": "json"},
+			args: []string{"-config", "$FILE", "-workers", "7", "-storage", "sqlite", "-admin", " @flag_admin "},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.Workers != 7 || cfg.Storage != "sqlite" || cfg.AdminUsername != "flag_admin" {
+					t.Errorf("flaglar qo'llanmadi: %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "nol qiymatli flag ham qo'llanadi",
+			args: []string{"-new-badge-window", "0s"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.NewBadgeWindow != 0 {
+					t.Errorf("NewBadgeWindow = %v, want 0", cfg.NewBadgeWindow)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearBotEnv(t)
+			path := writeConfigFile(t, file)
+			for name, value := range tt.env {
+				t.Setenv(name, strings.ReplaceAll(value, "$FILE", path))
+			}
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				args[i] = strings.ReplaceAll(arg, "$FILE", path)
+			}
+
+			cfg, err := loadConfig(args)
+			if err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+			cfg.ConfigFile = ""
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{name: "noma'lum flag", args: []string{"-nope"}, want: "not defined"},
+		{name: "fayl topilmadi", args: []string{"-config", "/nonexistent/config.yaml"}, want: "sozlamalar faylini o'qishda xatolik"},
+		{name: "faylda noma'lum maydon", file: "workerz: 3\n", want: "workerz"},
+		{name: "noto'g'ri butun son", env: map[string]string{"BOT_WORKERS": "ko'p"}, want: "BOT_WORKERS noto'g'ri"},
+		{name: "noto'g'ri kasr son", env: map[string]string{"BOT_SEND_CHAT_RATE": "x"}, want: "BOT_SEND_CHAT_RATE noto'g'ri"},
+		{name: "noto'g'ri davomiylik", env: map[string]string{"BOT_USER_STATE_TTL": "bir soat"}, want: "BOT_USER_STATE_TTL noto'g'ri"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearBotEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append(args, "-config", writeConfigFile(t, tt.file))
+			}
+
+			_, err := loadConfig(args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := func() Config {
+		cfg := defaultConfig()
+		cfg.BotToken = testToken
+		cfg.AdminUsername = "admin"
+		cfg.PrivateChannel = "-1001234567890"
+		return cfg
+	}
+
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   []string // bo'sh bo'lsa - xatolik yo'q
+	}{
+		{name: "to'g'ri sozlamalar", modify: func(cfg *Config) {}},
+		{name: "token yo'q", modify: func(cfg *Config) { cfg.BotToken = "" }, want: []string{"bot tokeni berilmagan"}},
+		{name: "token formati", modify: func(cfg *Config) { cfg.BotToken = "abc" }, want: []string{"bot tokeni noto'g'ri formatda"}},
+		{name: "admin yo'q", modify: func(cfg *Config) { cfg.AdminUsername = "" }, want: []string{"asosiy admin berilmagan"}},
+		{name: "kanal prefiksi", modify: func(cfg *Config) { cfg.PrivateChannel = "12345" }, want: []string{"-100 bilan boshlanishi kerak"}},
+		{name: "kanal raqam emas", modify: func(cfg *Config) { cfg.PrivateChannel = "-100abc" }, want: []string{"raqam bo'lishi kerak"}},
+		{
+			name: "dry-run uchun token va kanal kerak emas",
+			modify: func(cfg *Config) {
+				cfg.MigrateDryRun, cfg.BotToken, cfg.AdminUsername, cfg.PrivateChannel = true, "", "", ""
+			},
+		},
+		{name: "fayl ID chati", modify: func(cfg *Config) { cfg.FileIDChat = "123456789" }},
+		{name: "fayl ID chati raqam emas", modify: func(cfg *Config) { cfg.FileIDChat = "@scratch" }, want: []string{"file_id_chat raqam bo'lishi kerak"}},
+		{name: "ombor turi", modify: func(cfg *Config) { cfg.Storage = "redis" }, want: []string{`noma'lum ombor turi "redis"`}},
+		{name: "navigatsiya", modify: func(cfg *Config) { cfg.Navigation = "tabs" }, want: []string{`noma'lum navigatsiya rejimi "tabs"`}},
+		{name: "ma'lumotlar fayli", modify: func(cfg *Config) { cfg.DataFile = "" }, want: []string{"ma'lumotlar fayli ko'rsatilmagan"}},
+		{name: "sqlite fayli json uchun kerak emas", modify: func(cfg *Config) { cfg.SQLiteFile = "" }},
+		{
+			name:   "sqlite fayli",
+			modify: func(cfg *Config) { cfg.Storage, cfg.SQLiteFile = "sqlite", "" },
+			want:   []string{"SQLite bazasi fayli ko'rsatilmagan"},
+		},
+		{name: "jurnal papkasi", modify: func(cfg *Config) { cfg.LogsDir = "" }, want: []string{"jurnal papkasi ko'rsatilmagan"}},
+		{name: "holat muddati", modify: func(cfg *Config) { cfg.UserStateTTL = 0 }, want: []string{"user_state_ttl musbat"}},
+		{name: "yangi belgisi o'chirilgan", modify: func(cfg *Config) { cfg.NewBadgeWindow = 0 }},
+		{name: "yangi belgisi manfiy", modify: func(cfg *Config) { cfg.NewBadgeWindow = -time.Hour }, want: []string{"new_badge_window manfiy"}},
+		{name: "ishchilar", modify: func(cfg *Config) { cfg.Workers = 0 }, want: []string{"workers kamida 1"}},
+		{name: "yuborish tezligi", modify: func(cfg *Config) { cfg.SendChatRate = 0 }, want: []string{"send_chat_rate musbat"}},
+		{name: "foydalanuvchi tezligi", modify: func(cfg *Config) { cfg.UserRateLimit = -1 }, want: []string{"user_rate_limit musbat"}},
+		{name: "qayta urinishlar", modify: func(cfg *Config) { cfg.SendMaxRetries = -1 }, want: []string{"send_max_retries manfiy"}},
+		{
+			name:   "barcha xatoliklar birdaniga",
+			modify: func(cfg *Config) { cfg.BotToken, cfg.Workers, cfg.Storage = "", 0, "x" },
+			want:   []string{"bot tokeni berilmagan", "workers kamida 1", "noma'lum ombor turi"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			err := cfg.validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("validate() = nil, want %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("validate() = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
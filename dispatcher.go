@@ -0,0 +1,56 @@
+package main
+
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// dispatcher - yangilanishlarni ishchi goroutinelar orasida taqsimlaydi.
+// Bitta foydalanuvchining yangilanishlari doim bitta ishchiga tushadi, shuning
+// uchun ular kelgan tartibda bajariladi, turli foydalanuvchilar esa parallel.
+type dispatcher struct {
+	queues []chan tgbotapi.Update
+	wg     sync.WaitGroup
+}
+
+// Har bir ishchi navbatining sig'imi. Navbat to'lsa, yangilanishlarni qabul qilish kutadi.
+const dispatcherQueueSize = 100
+
+func newDispatcher(workers int, handle func(update tgbotapi.Update)) *dispatcher {
+	d := &dispatcher{queues: make([]chan tgbotapi.Update, workers)}
+	for i := range d.queues {
+		queue := make(chan tgbotapi.Update, dispatcherQueueSize)
+		d.queues[i] = queue
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for update := range queue {
+				handle(update)
+			}
+		}()
+	}
+	return d
+}
+
+// Yangilanishni foydalanuvchiga biriktirilgan ishchiga yuborish
+func (d *dispatcher) Dispatch(update tgbotapi.Update) {
+	index := uint64(updateUserID(update)) % uint64(len(d.queues))
+	d.queues[index] <- update
+}
+
+// Yangi yangilanishlarni qabul qilishni to'xtatib, navbatdagilarini tugatish
+func (d *dispatcher) Stop() {
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.wg.Wait()
+}
+
+// Yangilanish qaysi foydalanuvchiga tegishli ekanligini aniqlash
+func updateUserID(update tgbotapi.Update) int64 {
+	if user := update.SentFrom(); user != nil {
+		return user.ID
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func messageUpdate(userID int64, text string) tgbotapi.Update {
+	return tgbotapi.Update{Message: &tgbotapi.Message{
+		From: &tgbotapi.User{ID: userID},
+		Chat: &tgbotapi.Chat{ID: userID},
+		Text: text,
+	}}
+}
+
+// Bitta foydalanuvchining yangilanishlari kelgan tartibda bajariladi
+func TestDispatcherPerUserOrder(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[int64][]string)
+	d := newDispatcher(4, func(update tgbotapi.Update) {
+		// Sekin ishlov berish boshqa ishchilarga o'tib ketishga imkon beradi
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		got[update.Message.From.ID] = append(got[update.Message.From.ID], update.Message.Text)
+		mu.Unlock()
+	})
+
+	want := make(map[int64][]string)
+	for i := 0; i < 20; i++ {
+		for userID := int64(1); userID <= 6; userID++ {
+			text := string(rune('a' + i))
+			want[userID] = append(want[userID], text)
+			d.Dispatch(messageUpdate(userID, text))
+		}
+	}
+	d.Stop()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tartib buzildi:\n got  %v\n want %v", got, want)
+	}
+}
+
+// Turli foydalanuvchilar parallel bajariladi: biri band bo'lsa, boshqasi kutmaydi
+func TestDispatcherUsersRunInParallel(t *testing.T) {
+	release := make(chan struct{})
+	done := make(chan int64, 1)
+	d := newDispatcher(2, func(update tgbotapi.Update) {
+		if update.Message.From.ID == 2 {
+			<-release
+		}
+		done <- update.Message.From.ID
+	})
+
+	d.Dispatch(messageUpdate(2, "sekin"))
+	d.Dispatch(messageUpdate(3, "tez"))
+	select {
+	case userID := <-done:
+		if userID != 3 {
+			t.Errorf("birinchi tugagan foydalanuvchi %d, want 3", userID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("boshqa foydalanuvchi band ishchini kutib qoldi")
+	}
+	close(release)
+	d.Stop()
+}
+
+func TestUpdateUserID(t *testing.T) {
+	tests := []struct {
+		name   string
+		update tgbotapi.Update
+		want   int64
+	}{
+		{"xabar", messageUpdate(7, "salom"), 7},
+		{"callback", tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{From: &tgbotapi.User{ID: 8}}}, 8},
+		{"foydalanuvchisiz", tgbotapi.Update{ChannelPost: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: -1}}}, 0},
+	}
+	for _, tt := range tests {
+		if got := updateUserID(tt.update); got != tt.want {
+			t.Errorf("%s: updateUserID = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
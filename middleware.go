@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// updateHandler - bitta yangilanishni qayta ishlovchi funksiya
+type updateHandler func(bot *tgbotapi.BotAPI, update tgbotapi.Update)
+
+// middleware - handlerni o'rab, umumiy ishlarni (xatolik, jurnal, ruxsat...) bajaradi
+type middleware func(next updateHandler) updateHandler
+
+// Middlewarelarni zanjirga ulash. Birinchisi eng tashqi qatlam bo'ladi.
+func chain(handler updateHandler, middlewares ...middleware) updateHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Yangilanishni yuborgan foydalanuvchi
+func updateUser(update tgbotapi.Update) *tgbotapi.User {
+	switch {
+	case update.Message != nil:
+		return update.Message.From
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.From
+	}
+	return nil
+}
+
+// Javob yuboriladigan chat. Inline rejimdagi callbacklarda Message bo'lmaydi,
+// shunda foydalanuvchining shaxsiy chati ishlatiladi.
+func updateChatID(update tgbotapi.Update) int64 {
+	switch {
+	case update.Message != nil:
+		return update.Message.Chat.ID
+	case update.CallbackQuery != nil:
+		return callbackChatID(update.CallbackQuery)
+	}
+	return 0
+}
+
+func callbackChatID(callbackQuery *tgbotapi.CallbackQuery) int64 {
+	if callbackQuery.Message != nil && callbackQuery.Message.Chat != nil {
+		return callbackQuery.Message.Chat.ID
+	}
+	return callbackQuery.From.ID
+}
+
+// Callback ma'lumotidan amal nomini ajratish
+func callbackAction(callbackQuery *tgbotapi.CallbackQuery) string {
+	action, _, _ := strings.Cut(callbackQuery.Data, ":")
+	return action
+}
+
+// Foydalanuvchiga rad javobini berish (callback bo'lsa ogohlantirish oynasida)
+func rejectUpdate(bot *tgbotapi.BotAPI, update tgbotapi.Update, text string) {
+	if update.CallbackQuery != nil {
+		answer := tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, text)
+		outbox.Request(answer)
+		return
+	}
+	sendMessage(bot, updateChatID(update), text)
+}
+
+// 1. Panikani ushlash: bot yiqilmaydi, foydalanuvchi jarayoni tozalanadi, adminlar ogohlantiriladi
+func recoverPanics(next updateHandler) updateHandler {
+	return func(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			log.Printf("Yangilanishni qayta ishlashda panika: %v\n%s", recovered, debug.Stack())
+
+			chatID := updateChatID(update)
+			if user := updateUser(update); user != nil {
+				resetUserState(user.ID)
+				persistUserState(user.ID, chatID)
+			}
+			if chatID != 0 {
+				sendMessage(bot, chatID, "Kutilmagan xatolik yuz berdi. Iltimos, /start buyrug'i bilan qaytadan boshlang.")
+			}
+			notifyAdminsAboutPanic(bot, update, recovered)
+		}()
+		next(bot, update)
+	}
+}
+
+// Adminlarga panika haqida xabar berish oralig'i (xabarlar oqimining oldini olish uchun)
+const panicNotifyInterval = time.Minute
+
+var panicNotify struct {
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+func notifyAdminsAboutPanic(bot *tgbotapi.BotAPI, update tgbotapi.Update, recovered any) {
+	panicNotify.mu.Lock()
+	if time.Since(panicNotify.last) < panicNotifyInterval {
+		panicNotify.suppressed++
+		panicNotify.mu.Unlock()
+		return
+	}
+	suppressed := panicNotify.suppressed
+	panicNotify.last = time.Now()
+	panicNotify.suppressed = 0
+	panicNotify.mu.Unlock()
+
+	text := fmt.Sprintf("⚠️ Botda kutilmagan xatolik:\n%v\n\n%s", recovered, describeUpdate(update))
+	if suppressed > 0 {
+		text += fmt.Sprintf("\n\nOldingi xabardan beri yana %d ta xatolik bo'ldi (jurnalga qarang).", suppressed)
+	}
+
+	users, err := store.Users()
+	if err != nil {
+		log.Printf("Adminlarni aniqlashda xatolik: %v", err)
+		return
+	}
+	for _, user := range users {
+		if isAdmin(user.Username) {
+			sendMessage(bot, user.ID, text)
+		}
+	}
+}
+
+// Yangilanishni qisqacha tavsiflash (jurnal va xabarlar uchun)
+func describeUpdate(update tgbotapi.Update) string {
+	description := "Yangilanish"
+	if user := updateUser(update); user != nil {
+		description = fmt.Sprintf("Foydalanuvchi: @%s (ID: %d)", user.UserName, user.ID)
+	}
+	switch {
+	case update.Message != nil:
+		description += fmt.Sprintf("\nXabar: %s", update.Message.Text)
+	case update.CallbackQuery != nil:
+		description += fmt.Sprintf("\nCallback: %s", update.CallbackQuery.Data)
+	}
+	return description
+}
+
+// Foydalanuvchi ketma-ket chegarasiz yuborishi mumkin bo'lgan yangilanishlar
+const userRateBurst = 10
+
+// 2. Foydalanuvchi yuboradigan yangilanishlar tezligini cheklash.
+// Chegaradan oshgan yangilanishlar tashlanadi, foydalanuvchi bir marta ogohlantiriladi.
+func limitUserRate(rate float64, burst int) middleware {
+	limiters := newLimiterSet(rate, burst)
+	var warnedMu sync.Mutex
+	warned := make(map[int64]bool)
+
+	return func(next updateHandler) updateHandler {
+		return func(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+			user := updateUser(update)
+			if user == nil {
+				next(bot, update)
+				return
+			}
+
+			allowed := limiters.get(user.ID).allow()
+			warnedMu.Lock()
+			alreadyWarned := warned[user.ID]
+			if allowed {
+				delete(warned, user.ID)
+			} else {
+				warned[user.ID] = true
+			}
+			warnedMu.Unlock()
+
+			if allowed {
+				next(bot, update)
+				return
+			}
+			if update.CallbackQuery != nil {
+				outbox.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, "⏳ Juda tez! Biroz kuting."))
+			} else if !alreadyWarned {
+				sendMessage(bot, updateChatID(update), "⏳ Juda ko'p so'rov yuborildi. Iltimos, biroz kuting.")
+			}
+		}
+	}
+}
+
+// 3. Foydalanuvchini eslab qolish va uning jarayon holatini boshqarish
+func trackUserState(next updateHandler) updateHandler {
+	return func(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+		user := updateUser(update)
+		if user == nil {
+			return
+		}
+		chatID := updateChatID(update)
+
+		rememberUser(user)
+		applyEntryRenames(user.ID)
+		if update.Message != nil {
+			expireUserState(bot, user.ID, chatID)
+		}
+		next(bot, update)
+		persistUserState(user.ID, chatID)
+	}
+}
+
+// 4. Harakatlarni qayd qilish
+func logActions(next updateHandler) updateHandler {
+	return func(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+		if update.CallbackQuery != nil {
+			logUserAction(update.CallbackQuery.From, fmt.Sprintf("Callback: %s", callbackAction(update.CallbackQuery)), "")
+		}
+		next(bot, update)
+	}
+}
+
+// 5. Admin huquqini tekshirish. Handlerning adminOnly belgisi router orqali
+// aniqlanadi. Jarayon o'rtasida adminlikdan olingan foydalanuvchining holati
+// tozalanadi va xabari oddiy foydalanuvchinikidek ishlanadi.
+func requireAdmin(routes *router) middleware {
+	return func(next updateHandler) updateHandler {
+		return func(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+			user := updateUser(update)
+			if user == nil {
+				next(bot, update)
+				return
+			}
+
+			state, exists := findUserState(user.ID)
+			if exists && routes.isAdminState(state.State) && !isAdmin(user.UserName) {
+				resetUserState(user.ID)
+			}
+
+			found, matched := routes.match(update, state)
+			if matched && found.adminOnly && !isAdmin(user.UserName) {
+				rejectUpdate(bot, update, "Bu amal faqat adminlar uchun.")
+				return
+			}
+			next(bot, update)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Birinchi middleware eng tashqi qatlam bo'ladi
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	layer := func(name string) middleware {
+		return func(next updateHandler) updateHandler {
+			return func(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+				calls = append(calls, name+" >")
+				next(bot, update)
+				calls = append(calls, "< "+name)
+			}
+		}
+	}
+	handler := chain(func(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+		calls = append(calls, "handler")
+	}, layer("a"), layer("b"))
+
+	handler(nil, tgbotapi.Update{})
+	want := []string{"a >", "b >", "handler", "< b", "< a"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("chaqiruvlar = %v, want %v", calls, want)
+	}
+}
+
+func TestRecoverPanics(t *testing.T) {
+	bot, fake := setupTestBot(t)
+	store.SaveUser(UserInfo{ID: 1, Username: "boss"})
+	panicNotify.last = time.Time{}
+	state := getUserState(42)
+	state.State = STATE_UPDATE_BIO
+
+	handler := chain(func(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+		panic("sinov")
+	}, recoverPanics)
+	handler(bot, messageUpdate(42, "salom"))
+
+	if state, _ := findUserState(42); state.State != STATE_NONE {
+		t.Errorf("panikadan keyin holat = %q, want %q", state.State, STATE_NONE)
+	}
+	chats := map[string]string{}
+	for _, request := range fake.take() {
+		chats[request.Form.Get("chat_id")] = request.Form.Get("text")
+	}
+	if !strings.Contains(chats["42"], "Kutilmagan xatolik") {
+		t.Errorf("foydalanuvchiga xabar yuborilmadi: %v", chats)
+	}
+	if !strings.Contains(chats["1"], "sinov") {
+		t.Errorf("adminga xabar yuborilmadi: %v", chats)
+	}
+}
+
+func TestLimitUserRate(t *testing.T) {
+	bot, fake := setupTestBot(t)
+	handled := 0
+	handler := chain(func(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+		handled++
+	}, limitUserRate(1, 2))
+
+	for i := 0; i < 5; i++ {
+		handler(bot, messageUpdate(42, "salom"))
+	}
+	handler(bot, messageUpdate(43, "salom"))
+
+	if handled != 3 {
+		t.Errorf("bajarilgan yangilanishlar = %d, want 3 (42 uchun 2 ta, 43 uchun 1 ta)", handled)
+	}
+	// Ogohlantirish bir marta yuboriladi
+	if requests := fake.take(); len(requests) != 1 {
+		t.Errorf("ogohlantirishlar soni = %d, want 1", len(requests))
+	}
+
+	time.Sleep(time.Second)
+	handler(bot, messageUpdate(42, "salom"))
+	if handled != 4 {
+		t.Errorf("kutishdan keyin yangilanish bajarilmadi")
+	}
+}
@@ -0,0 +1,644 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// jsonMigration - tutorial_data.json faylini bir versiyadan keyingisiga o'tkazuvchi qadam.
+// Migratsiyalar xom JSON hujjat (map) ustida ishlaydi, shuning uchun eski
+// tuzilishdagi fayllarni ham joriy Go tiplariga bog'lanmasdan o'zgartirish mumkin.
+type jsonMigration struct {
+	version     int // migratsiyadan keyingi versiya
+	description string
+	// apply hujjatni joyida o'zgartiradi va qilingan o'zgarishlar ro'yxatini qaytaradi
+	apply func(doc map[string]any) ([]string, error)
+}
+
+// Migratsiyalar reyestri. Yangi maydon qo'shilganda ro'yxat oxiriga yangi
+// qadam qo'shiladi; mavjud qadamlar hech qachon o'zgartirilmaydi.
+var jsonMigrations = []jsonMigration{
+	{
+		version:     1,
+		description: "Asosiy bo'limlar (tutorials, admins, stories) mavjudligini ta'minlash",
+		apply: func(doc map[string]any) ([]string, error) {
+			var changes []string
+			for _, key := range []string{"tutorials", "admins", "stories"} {
+				if value, exists := doc[key]; !exists || value == nil {
+					doc[key] = map[string]any{}
+					changes = append(changes, fmt.Sprintf("'%s' bo'sh obyekt sifatida qo'shildi", key))
+				}
+			}
+			return changes, nil
+		},
+	},
+	{
+		version:     2,
+		description: "Tutorials va geroylar tarixini umumiy bo'limlar (sections) ga o'tkazish",
+		apply: func(doc map[string]any) ([]string, error) {
+			sections := map[string]any{}
+			entries := map[string]any{}
+			legacy := []struct {
+				key, name, emoji string
+			}{
+				{"tutorials", "Tutorials", "📚"},
+				{"stories", "Geroylar tarixi", "📖"},
+			}
+
+			var changes []string
+			for i, section := range legacy {
+				sections[section.key] = map[string]any{
+					"id":       section.key,
+					"name":     section.name,
+					"emoji":    section.emoji,
+					"position": json.Number(fmt.Sprint(i + 1)),
+				}
+				items, ok := doc[section.key].(map[string]any)
+				if !ok {
+					items = map[string]any{}
+				}
+				entries[section.key] = items
+				delete(doc, section.key)
+				changes = append(changes, fmt.Sprintf("%d ta yozuv '%s' bo'limiga ko'chirildi", len(items), section.key))
+			}
+			doc["sections"] = sections
+			doc["entries"] = entries
+			return changes, nil
+		},
+	},
+	{
+		version:     3,
+		description: "Rollar ro'yxatini (roles) ma'lumotlarga ko'chirish",
+		apply: func(doc map[string]any) ([]string, error) {
+			roles := map[string]any{}
+			for i, name := range []string{"Marksman/ADK", "Tank", "Fighter", "Assassin", "Support", "Mage"} {
+				roles[name] = map[string]any{"name": name, "position": json.Number(fmt.Sprint(i + 1))}
+			}
+
+			// Yozuvlarda uchraydigan, lekin ro'yxatda bo'lmagan rollar ham saqlanadi
+			var changes []string
+			sections, _ := doc["entries"].(map[string]any)
+			for _, items := range sections {
+				items, _ := items.(map[string]any)
+				for _, item := range items {
+					item, _ := item.(map[string]any)
+					name, _ := item["role"].(string)
+					if _, exists := roles[name]; name == "" || exists {
+						continue
+					}
+					roles[name] = map[string]any{"name": name, "position": json.Number(fmt.Sprint(len(roles) + 1))}
+					changes = append(changes, fmt.Sprintf("yozuvlardagi '%s' roli ro'yxatga qo'shildi", name))
+				}
+			}
+			doc["roles"] = roles
+			changes = append(changes, fmt.Sprintf("%d ta rol qo'shildi", len(roles)))
+			return changes, nil
+		},
+	},
+	{
+		version:     4,
+		description: "Yozuvlarning yagona rolini rollar ro'yxatiga (roles) aylantirish",
+		apply: func(doc map[string]any) ([]string, error) {
+			converted := 0
+			sections, _ := doc["entries"].(map[string]any)
+			for _, items := range sections {
+				items, _ := items.(map[string]any)
+				for _, item := range items {
+					item, ok := item.(map[string]any)
+					if !ok {
+						continue
+					}
+					roles := []any{}
+					if role, _ := item["role"].(string); role != "" {
+						roles = append(roles, role)
+					}
+					item["roles"] = roles
+					delete(item, "role")
+					converted++
+				}
+			}
+			if converted == 0 {
+				return nil, nil
+			}
+			return []string{fmt.Sprintf("%d ta yozuvning roli ro'yxatga aylantirildi", converted)}, nil
+		},
+	},
+	{
+		version:     5,
+		description: "Yozuvlarga o'zgarmas qisqa ID berish",
+		apply: func(doc map[string]any) ([]string, error) {
+			taken := map[string]bool{}
+			var missing []map[string]any
+			sections, _ := doc["entries"].(map[string]any)
+			for _, items := range sections {
+				items, _ := items.(map[string]any)
+				for _, item := range items {
+					item, ok := item.(map[string]any)
+					if !ok {
+						continue
+					}
+					if id, _ := item["id"].(string); id != "" {
+						taken[id] = true
+						continue
+					}
+					missing = append(missing, item)
+				}
+			}
+			for _, item := range missing {
+				id, err := newEntryID(func(id string) (bool, error) { return taken[id], nil })
+				if err != nil {
+					return nil, err
+				}
+				taken[id] = true
+				item["id"] = id
+			}
+			if len(missing) == 0 {
+				return nil, nil
+			}
+			return []string{fmt.Sprintf("%d ta yozuvga ID berildi", len(missing))}, nil
+		},
+	},
+	{
+		version:     6,
+		description: "Video ID larini nom va tavsif saqlanadigan obyektlarga aylantirish",
+		apply: func(doc map[string]any) ([]string, error) {
+			converted := 0
+			sections, _ := doc["entries"].(map[string]any)
+			for _, items := range sections {
+				items, _ := items.(map[string]any)
+				for _, item := range items {
+					item, ok := item.(map[string]any)
+					if !ok {
+						continue
+					}
+					videos, _ := item["videos"].([]any)
+					objects := make([]any, 0, len(videos))
+					for _, video := range videos {
+						switch id := video.(type) {
+						case string:
+							objects = append(objects, map[string]any{"id": id})
+							converted++
+						case json.Number:
+							objects = append(objects, map[string]any{"id": id.String()})
+							converted++
+						default:
+							objects = append(objects, video)
+						}
+					}
+					item["videos"] = objects
+				}
+			}
+			if converted == 0 {
+				return nil, nil
+			}
+			return []string{fmt.Sprintf("%d ta video obyektga aylantirildi", converted)}, nil
+		},
+	},
+	{
+		version:     7,
+		description: "Kategoriya ajratuvchisini \" / \" ko'rinishiga keltirish",
+		apply: func(doc map[string]any) ([]string, error) {
+			var changes []string
+			renamed := 0
+			sections, _ := doc["entries"].(map[string]any)
+			for sectionID, items := range sections {
+				items, _ := items.(map[string]any)
+				for _, title := range sortedKeys(items) {
+					normalized := legacyEntryPath(title)
+					if normalized == title {
+						continue
+					}
+					if _, taken := items[normalized]; taken {
+						changes = append(changes, fmt.Sprintf("%s: '%s' o'zgarmadi - '%s' nomi band", sectionID, title, normalized))
+						continue
+					}
+					items[normalized] = items[title]
+					delete(items, title)
+					renamed++
+				}
+			}
+			if renamed > 0 {
+				changes = append(changes, fmt.Sprintf("%d ta yozuv nomidagi / belgisi \" / \" ga almashtirildi", renamed))
+			}
+			return changes, nil
+		},
+	},
+}
+
+// Oldin kategoriyalar har qanday "/" belgisida ajratilardi ("Alucard/Laning"
+// ham kategoriya edi). Endi ajratuvchi faqat " / ", shuning uchun eski
+// nomlar ko'rinishi o'zgarmasligi uchun shu ko'rinishga keltiriladi.
+func legacyEntryPath(title string) string {
+	var parts []string
+	for _, part := range strings.Split(title, "/") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return title
+	}
+	return strings.Join(parts, " / ")
+}
+
+func sortedKeys(items map[string]any) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Joriy sxema versiyasi - oxirgi migratsiya versiyasi
+var currentSchemaVersion = jsonMigrations[len(jsonMigrations)-1].version
+
+// Bajarilgan migratsiya haqida hisobot
+type migrationReport struct {
+	version     int
+	description string
+	changes     []string
+}
+
+// Hujjatdagi sxema versiyasini o'qish (maydon bo'lmasa 0)
+func documentSchemaVersion(doc map[string]any) (int, error) {
+	raw, exists := doc["schema_version"]
+	if !exists || raw == nil {
+		return 0, nil
+	}
+	number, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("schema_version raqam bo'lishi kerak, %T topildi", raw)
+	}
+	version, err := number.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("schema_version noto'g'ri: %w", err)
+	}
+	return int(version), nil
+}
+
+// Hujjatni joriy versiyagacha bosqichma-bosqich yangilash
+func migrateDocument(doc map[string]any) ([]migrationReport, error) {
+	version, err := documentSchemaVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+	if version > currentSchemaVersion {
+		return nil, fmt.Errorf("fayl sxema versiyasi (%d) botning versiyasidan (%d) yangiroq", version, currentSchemaVersion)
+	}
+
+	var reports []migrationReport
+	for _, m := range jsonMigrations {
+		if m.version <= version {
+			continue
+		}
+		changes, err := m.apply(doc)
+		if err != nil {
+			return reports, fmt.Errorf("%d-migratsiya (%s) xatoligi: %w", m.version, m.description, err)
+		}
+		doc["schema_version"] = json.Number(fmt.Sprint(m.version))
+		version = m.version
+		reports = append(reports, migrationReport{version: m.version, description: m.description, changes: changes})
+	}
+	return reports, nil
+}
+
+// JSON baytlarni o'qib, kerak bo'lsa migratsiya qilib BotData ga o'tkazish
+func decodeBotData(fileData []byte) (BotData, []migrationReport, error) {
+	doc, err := decodeDocument(fileData)
+	if err != nil {
+		return BotData{}, nil, err
+	}
+	reports, err := migrateDocument(doc)
+	if err != nil {
+		return BotData{}, nil, err
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return BotData{}, nil, fmt.Errorf("migratsiyadan keyin kodlashda xatolik: %w", err)
+	}
+	data := BotData{}
+	if err := json.Unmarshal(migrated, &data); err != nil {
+		return BotData{}, nil, fmt.Errorf("JSON dekodlashda xatolik: %w", err)
+	}
+	data.ensureMaps()
+	return data, reports, nil
+}
+
+// Raqamlarni aniq saqlagan holda JSON hujjatni map ga o'qish
+func decodeDocument(fileData []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(fileData))
+	decoder.UseNumber()
+	doc := map[string]any{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("JSON dekodlashda xatolik: %w", err)
+	}
+	return doc, nil
+}
+
+// sqliteMigration - SQLite sxemasini yangilovchi qadam (PRAGMA user_version bo'yicha)
+type sqliteMigration struct {
+	version     int
+	description string
+	statements  string
+	// SQL bilan ifodalash qiyin bo'lgan o'zgarishlar uchun (statements dan keyin bajariladi)
+	apply func(tx *sql.Tx) error
+}
+
+var sqliteMigrations = []sqliteMigration{
+	{version: 1, description: "Boshlang'ich sxema", statements: sqliteSchema},
+	{
+		version:     2,
+		description: "Foydalanuvchi holatlari jadvali",
+		statements: `
+CREATE TABLE IF NOT EXISTS user_states (
+	user_id    INTEGER PRIMARY KEY,
+	chat_id    INTEGER NOT NULL,
+	state      TEXT NOT NULL DEFAULT '',
+	temp_data  TEXT NOT NULL DEFAULT '{}',
+	updated_at TEXT NOT NULL DEFAULT ''
+);`,
+	},
+	{
+		version:     3,
+		description: "Tutorials va geroylar tarixini umumiy bo'limlar jadvaliga o'tkazish",
+		statements: `
+CREATE TABLE sections (
+	id       TEXT PRIMARY KEY,
+	name     TEXT NOT NULL,
+	emoji    TEXT NOT NULL DEFAULT '',
+	position INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE entries (
+	section_id TEXT NOT NULL,
+	title      TEXT NOT NULL,
+	bio        TEXT NOT NULL DEFAULT '',
+	role       TEXT NOT NULL DEFAULT '',
+	videos     TEXT NOT NULL DEFAULT '[]',
+	PRIMARY KEY (section_id, title)
+);
+INSERT INTO sections (id, name, emoji, position) VALUES
+	('tutorials', 'Tutorials', '📚', 1),
+	('stories', 'Geroylar tarixi', '📖', 2);
+INSERT INTO entries (section_id, title, bio, role, videos)
+	SELECT 'tutorials', title, bio, role, videos FROM tutorials;
+INSERT INTO entries (section_id, title, bio, role, videos)
+	SELECT 'stories', title, bio, role, videos FROM stories;
+DROP TABLE tutorials;
+DROP TABLE stories;`,
+	},
+	{
+		version:     4,
+		description: "Rollar jadvali",
+		statements: `
+CREATE TABLE roles (
+	name     TEXT PRIMARY KEY,
+	position INTEGER NOT NULL DEFAULT 0,
+	hidden   INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO roles (name, position) VALUES
+	('Marksman/ADK', 1),
+	('Tank', 2),
+	('Fighter', 3),
+	('Assassin', 4),
+	('Support', 5),
+	('Mage', 6);
+INSERT OR IGNORE INTO roles (name, position)
+	SELECT DISTINCT role, 7 FROM entries WHERE role != '';`,
+	},
+	{
+		version:     5,
+		description: "Yozuvlarda bir nechta rol va teglar",
+		statements: `
+ALTER TABLE entries ADD COLUMN roles TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE entries ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
+UPDATE entries SET roles = json_array(role) WHERE role != '';
+ALTER TABLE entries DROP COLUMN role;`,
+	},
+	{
+		version:     6,
+		description: "Yozuvlar tartibi, qadash va yaratilgan/yangilangan vaqt",
+		statements: `
+ALTER TABLE entries ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE entries ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE entries ADD COLUMN created_at TEXT NOT NULL DEFAULT '';
+ALTER TABLE entries ADD COLUMN updated_at TEXT NOT NULL DEFAULT '';`,
+	},
+	{
+		version:     7,
+		description: "Yozuvlarga o'zgarmas qisqa ID berish",
+		statements: `
+ALTER TABLE entries ADD COLUMN id TEXT NOT NULL DEFAULT '';
+UPDATE entries SET id = lower(hex(randomblob(4)));
+CREATE UNIQUE INDEX entries_id ON entries(id);`,
+	},
+	{
+		version:     8,
+		description: "Video ID larini nom va tavsif saqlanadigan obyektlarga aylantirish",
+		statements: `
+UPDATE entries SET videos = (
+	SELECT json_group_array(json_object('id', CAST(value AS TEXT)))
+	FROM (SELECT value FROM json_each(entries.videos) ORDER BY key)
+) WHERE json_array_length(videos) > 0;`,
+	},
+	{
+		version:     9,
+		description: "Videolarni albom o'rniga alohida yuborish sozlamasi",
+		statements: `
+ALTER TABLE entries ADD COLUMN separate INTEGER NOT NULL DEFAULT 0;`,
+	},
+	{
+		version:     10,
+		description: "Kategoriya ajratuvchisini \" / \" ko'rinishiga keltirish",
+		apply:       normalizeSQLiteEntryPaths,
+	},
+	{
+		version:     11,
+		description: "JSON importi bajarilganini qayd qilish uchun meta jadvali",
+		// Avval import yozuvlar soni bo'yicha aniqlanardi - ma'lumotli bazalar
+		// import qilingan deb belgilanadi
+		statements: `
+CREATE TABLE meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL DEFAULT ''
+);
+INSERT INTO meta (key, value)
+	SELECT 'json_import', '' WHERE EXISTS (SELECT 1 FROM entries) OR EXISTS (SELECT 1 FROM admins);`,
+	},
+}
+
+// 10-migratsiya: "/" belgili nomlarni legacyEntryPath ko'rinishiga keltirish.
+// Yangi nom band bo'lsa, yozuv eski nomida qoladi.
+func normalizeSQLiteEntryPaths(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT section_id, title FROM entries WHERE title LIKE '%/%' ORDER BY section_id, title`)
+	if err != nil {
+		return err
+	}
+	type entryKey struct{ sectionID, title string }
+	var keys []entryKey
+	for rows.Next() {
+		var key entryKey
+		if err := rows.Scan(&key.sectionID, &key.title); err != nil {
+			rows.Close()
+			return err
+		}
+		keys = append(keys, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		normalized := legacyEntryPath(key.title)
+		if normalized == key.title {
+			continue
+		}
+		_, err := tx.Exec(`UPDATE entries SET title = ? WHERE section_id = ? AND title = ?
+			AND NOT EXISTS (SELECT 1 FROM entries WHERE section_id = ? AND title = ?)`,
+			normalized, key.sectionID, key.title, key.sectionID, normalized)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SQLite bazasidagi joriy sxema versiyasi
+func sqliteSchemaVersion(db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("sxema versiyasini o'qishda xatolik: %w", err)
+	}
+	return version, nil
+}
+
+// SQLite bazasini joriy versiyagacha yangilash. Har bir qadam alohida tranzaksiyada.
+func migrateSQLite(db *sql.DB) error {
+	version, err := sqliteSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	latest := sqliteMigrations[len(sqliteMigrations)-1].version
+	if version > latest {
+		return fmt.Errorf("baza sxema versiyasi (%d) botning versiyasidan (%d) yangiroq", version, latest)
+	}
+
+	for _, m := range sqliteMigrations {
+		if m.version <= version {
+			continue
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("tranzaksiyani boshlashda xatolik: %w", err)
+		}
+		if m.statements != "" {
+			if _, err := tx.Exec(m.statements); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("%d-migratsiya (%s) xatoligi: %w", m.version, m.description, err)
+			}
+		}
+		if m.apply != nil {
+			if err := m.apply(tx); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("%d-migratsiya (%s) xatoligi: %w", m.version, m.description, err)
+			}
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, m.version)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("sxema versiyasini yozishda xatolik: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("%d-migratsiyani yakunlashda xatolik: %w", m.version, err)
+		}
+		version = m.version
+	}
+	return nil
+}
+
+// Migratsiyalarni bajarmasdan, nima o'zgarishini chop etish (-migrate-dry-run)
+func dryRunMigrations(driver string) error {
+	switch driver {
+	case "json":
+		fileData, err := os.ReadFile(cfg.DataFile)
+		if os.IsNotExist(err) {
+			fmt.Printf("%s mavjud emas - migratsiya talab qilinmaydi.\n", cfg.DataFile)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		doc, err := decodeDocument(fileData)
+		if err != nil {
+			return err
+		}
+		version, err := documentSchemaVersion(doc)
+		if err != nil {
+			return err
+		}
+		reports, err := migrateDocument(doc)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: joriy versiya %d, kerakli versiya %d\n", cfg.DataFile, version, currentSchemaVersion)
+		printMigrationReports(reports)
+
+	case "sqlite":
+		if _, err := os.Stat(cfg.SQLiteFile); os.IsNotExist(err) {
+			fmt.Printf("%s mavjud emas - birinchi ishga tushishda yaratiladi.\n", cfg.SQLiteFile)
+			return nil
+		}
+		db, err := sql.Open("sqlite", "file:"+cfg.SQLiteFile+"?mode=ro")
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		version, err := sqliteSchemaVersion(db)
+		if err != nil {
+			return err
+		}
+		var reports []migrationReport
+		for _, m := range sqliteMigrations {
+			if m.version > version {
+				reports = append(reports, migrationReport{version: m.version, description: m.description})
+			}
+		}
+		fmt.Printf("%s: joriy versiya %d, kerakli versiya %d\n", cfg.SQLiteFile, version, sqliteMigrations[len(sqliteMigrations)-1].version)
+		printMigrationReports(reports)
+
+	default:
+		return fmt.Errorf("noma'lum ombor turi: %q", driver)
+	}
+	return nil
+}
+
+func printMigrationReports(reports []migrationReport) {
+	if len(reports) == 0 {
+		fmt.Println("Migratsiya talab qilinmaydi.")
+		return
+	}
+	for _, report := range reports {
+		fmt.Printf("-> v%d: %s\n", report.version, report.description)
+		if len(report.changes) == 0 {
+			fmt.Println("   (ma'lumotlarda o'zgarish yo'q)")
+		}
+		for _, change := range report.changes {
+			fmt.Printf("   - %s\n", change)
+		}
+	}
+}
+
+// Migratsiya hisobotini bir qatorli log uchun formatlash
+func describeMigrations(reports []migrationReport) string {
+	var parts []string
+	for _, report := range reports {
+		parts = append(parts, fmt.Sprintf("v%d (%s)", report.version, report.description))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Asl (versiyasiz) tutorial_data.json ko'rinishidagi fayl
+const legacyDocument = `{
+  "tutorials": {
+    "Chichi": {"bio": "Zor geroy", "role": "Fighter", "videos": ["4", "7"]},
+    "Alucard/Laning": {"bio": "", "role": "Jungler", "videos": []}
+  },
+  "admins": {"boss": {"username": "boss", "added_by": "root", "added_at": "2025-03-07T16:20:56+05:00"}},
+  "stories": {"Diggi": {"bio": "Hikoya", "role": "", "videos": ["9"]}}
+}`
+
+func TestMigrateDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		versions []int // bajarilishi kerak bo'lgan migratsiyalar
+		check    func(t *testing.T, data BotData)
+		wantErr  string
+	}{
+		{
+			name:     "versiyasiz fayl",
+			input:    legacyDocument,
+			versions: []int{1, 2, 3, 4, 5, 6, 7},
+			check: func(t *testing.T, data BotData) {
+				if len(data.Sections) != 2 || data.Sections["stories"].Name != "Geroylar tarixi" {
+					t.Errorf("Sections = %+v", data.Sections)
+				}
+				chichi := data.Entries["tutorials"]["Chichi"]
+				if !reflect.DeepEqual(chichi.Roles, []string{"Fighter"}) {
+					t.Errorf("Chichi.Roles = %v", chichi.Roles)
+				}
+				if !reflect.DeepEqual(chichi.Videos, []Video{{ID: "4"}, {ID: "7"}}) {
+					t.Errorf("Chichi.Videos = %+v", chichi.Videos)
+				}
+				if len(chichi.ID) != 8 {
+					t.Errorf("Chichi.ID = %q, 8 ta belgi kutilgan", chichi.ID)
+				}
+				if diggi := data.Entries["stories"]["Diggi"]; len(diggi.Roles) != 0 || diggi.Bio != "Hikoya" {
+					t.Errorf("Diggi = %+v", diggi)
+				}
+				if _, exists := data.Entries["tutorials"]["Alucard / Laning"]; !exists {
+					t.Errorf("kategoriya ajratuvchisi bir xillashtirilmadi: %v", data.Entries["tutorials"])
+				}
+				// Ro'yxatda bo'lmagan rol yozuvlardan qo'shiladi
+				if _, exists := data.Roles["Jungler"]; !exists || len(data.Roles) != 7 {
+					t.Errorf("Roles = %v", data.Roles)
+				}
+				if data.Admins["boss"].AddedBy != "root" {
+					t.Errorf("Admins = %+v", data.Admins)
+				}
+			},
+		},
+		{
+			name:     "bo'sh fayl",
+			input:    `{}`,
+			versions: []int{1, 2, 3, 4, 5, 6, 7},
+			check: func(t *testing.T, data BotData) {
+				if len(data.Sections) != 2 || len(data.Entries["tutorials"]) != 0 || len(data.Roles) != 6 {
+					t.Errorf("data = %+v", data)
+				}
+			},
+		},
+		{
+			name: "mavjud ID lar saqlanadi",
+			input: `{"schema_version": 4, "sections": {"tutorials": {"id": "tutorials", "name": "Tutorials"}},
+				"entries": {"tutorials": {"A": {"id": "abcd1234", "roles": []}, "B": {"roles": []}}}}`,
+			versions: []int{5, 6, 7},
+			check: func(t *testing.T, data BotData) {
+				a, b := data.Entries["tutorials"]["A"], data.Entries["tutorials"]["B"]
+				if a.ID != "abcd1234" {
+					t.Errorf("A.ID = %q, want abcd1234", a.ID)
+				}
+				if b.ID == "" || b.ID == a.ID {
+					t.Errorf("B.ID = %q", b.ID)
+				}
+			},
+		},
+		{
+			name: "raqamli va obyektli videolar",
+			input: `{"schema_version": 5, "entries": {"tutorials": {"A": {"id": "1", "videos":
+				["3", 5, {"id": "8", "title": "Kirish", "file_id": "f8"}]}}}}`,
+			versions: []int{6, 7},
+			check: func(t *testing.T, data BotData) {
+				want := []Video{{ID: "3"}, {ID: "5"}, {ID: "8", Title: "Kirish", FileID: "f8"}}
+				if got := data.Entries["tutorials"]["A"].Videos; !reflect.DeepEqual(got, want) {
+					t.Errorf("Videos = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "band nom o'zgartirilmaydi",
+			input: `{"schema_version": 6, "entries": {"tutorials": {
+				"AC/DC": {"id": "1"}, "AC / DC": {"id": "2"}, "A //B/ ": {"id": "3"}, "Marksman/ADK": {"id": "4"}}}}`,
+			versions: []int{7},
+			check: func(t *testing.T, data BotData) {
+				ids := make(map[string]string)
+				for title, entry := range data.Entries["tutorials"] {
+					ids[title] = entry.ID
+				}
+				want := map[string]string{"AC/DC": "1", "AC / DC": "2", "A / B": "3", "Marksman / ADK": "4"}
+				if !reflect.DeepEqual(ids, want) {
+					t.Errorf("titles = %v, want %v", ids, want)
+				}
+			},
+		},
+		{
+			name:  "joriy versiya",
+			input: fmt.Sprintf(`{"schema_version": %d}`, currentSchemaVersion),
+		},
+		{
+			name:    "yangiroq versiya",
+			input:   `{"schema_version": 99}`,
+			wantErr: "yangiroq",
+		},
+		{
+			name:    "versiya raqam emas",
+			input:   `{"schema_version": "7"}`,
+			wantErr: "raqam bo'lishi kerak",
+		},
+		{
+			name:    "buzilgan JSON",
+			input:   `{"tutorials": `,
+			wantErr: "JSON dekodlashda xatolik",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, reports, err := decodeBotData([]byte(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeBotData: %v", err)
+			}
+
+			var versions []int
+			for _, report := range reports {
+				versions = append(versions, report.version)
+			}
+			if !reflect.DeepEqual(versions, tt.versions) {
+				t.Errorf("migratsiyalar = %v, want %v", versions, tt.versions)
+			}
+			if tt.check != nil {
+				tt.check(t, data)
+			}
+		})
+	}
+}
+
+// Migratsiya qilingan hujjat qayta o'qilganda hech narsa o'zgarmasligi kerak
+func TestMigrateDocumentIdempotent(t *testing.T) {
+	doc, err := decodeDocument([]byte(legacyDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrateDocument(doc); err != nil {
+		t.Fatal(err)
+	}
+	reports, err := migrateDocument(doc)
+	if err != nil || len(reports) != 0 {
+		t.Errorf("ikkinchi migratsiya: reports = %v, err = %v", reports, err)
+	}
+	if version, _ := documentSchemaVersion(doc); version != currentSchemaVersion {
+		t.Errorf("schema_version = %d, want %d", version, currentSchemaVersion)
+	}
+}
+
+func TestLegacyEntryPath(t *testing.T) {
+	tests := []struct {
+		title, want string
+	}{
+		{"Chichi", "Chichi"},
+		{"Alucard/Laning", "Alucard / Laning"},
+		{"Alucard / Laning", "Alucard / Laning"},
+		{" A // B /C ", "A / B / C"},
+		{"/", "/"},
+	}
+	for _, tt := range tests {
+		if got := legacyEntryPath(tt.title); got != tt.want {
+			t.Errorf("legacyEntryPath(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+// Bazani berilgan versiyagacha migratsiya qilish (eski bazalarni yaratish uchun)
+func sqliteAtVersion(t *testing.T, version int) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "bot.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	for _, m := range sqliteMigrations[:version] {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Exec(m.statements); err != nil {
+			t.Fatalf("%d-migratsiya: %v", m.version, err)
+		}
+		if m.apply != nil {
+			if err := m.apply(tx); err != nil {
+				t.Fatalf("%d-migratsiya: %v", m.version, err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version)); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMigrateSQLite(t *testing.T) {
+	latest := sqliteMigrations[len(sqliteMigrations)-1].version
+
+	type row struct {
+		Title, Roles, Videos string
+		HasID                bool
+	}
+	tests := []struct {
+		name    string
+		version int
+		setup   string
+		want    []row
+		wantErr string
+	}{
+		{
+			name:    "boshlang'ich sxemadagi yozuvlar",
+			version: 1,
+			setup: `INSERT INTO tutorials (title, bio, role, videos) VALUES
+					('Chichi', 'Zor geroy', 'Fighter', '["4","7"]'),
+					('Alucard/Laning', '', 'Jungler', '[]');
+				INSERT INTO stories (title, bio, role, videos) VALUES ('Diggi', 'Hikoya', '', '["9"]');`,
+			want: []row{
+				{"Alucard / Laning", `["Jungler"]`, `[]`, true},
+				{"Chichi", `["Fighter"]`, `[{"id":"4"},{"id":"7"}]`, true},
+				{"Diggi", `[]`, `[{"id":"9"}]`, true},
+			},
+		},
+		{
+			name:    "video ID lari obyektga aylanadi",
+			version: 7,
+			setup: `INSERT INTO entries (section_id, title, id, videos) VALUES
+				('tutorials', 'A', 'aaaa0001', '["3",5]'), ('tutorials', 'B', 'aaaa0002', '[]');`,
+			want: []row{
+				{"A", `[]`, `[{"id":"3"},{"id":"5"}]`, true},
+				{"B", `[]`, `[]`, true},
+			},
+		},
+		{
+			name:    "band nom o'zgartirilmaydi",
+			version: 9,
+			setup: `INSERT INTO entries (section_id, title, id) VALUES
+				('tutorials', 'AC/DC', 'aaaa0001'), ('tutorials', 'AC / DC', 'aaaa0002'), ('stories', 'x//y ', 'aaaa0003');`,
+			want: []row{
+				{"AC / DC", `[]`, `[]`, true},
+				{"AC/DC", `[]`, `[]`, true},
+				{"x / y", `[]`, `[]`, true},
+			},
+		},
+		{
+			name:    "joriy versiya",
+			version: latest,
+		},
+		{
+			name:    "yangiroq versiya",
+			version: latest,
+			setup:   `PRAGMA user_version = 99`,
+			wantErr: "yangiroq",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sqliteAtVersion(t, tt.version)
+			if tt.setup != "" {
+				if _, err := db.Exec(tt.setup); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := migrateSQLite(db)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("migrateSQLite: %v", err)
+			}
+			if version, _ := sqliteSchemaVersion(db); version != latest {
+				t.Errorf("user_version = %d, want %d", version, latest)
+			}
+
+			rows, err := db.Query(`SELECT title, roles, videos, id != '' FROM entries ORDER BY title`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+			var got []row
+			for rows.Next() {
+				var r row
+				if err := rows.Scan(&r.Title, &r.Roles, &r.Videos, &r.HasID); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, r)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entries = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
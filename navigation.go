@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Navigatsiya rejimlari (sozlamalardagi "navigation"):
+// reply - har qadamda yangi xabar va pastki klaviatura;
+// inline - bo'lim -> rol -> yozuv ko'rish bitta xabar ichida, u joyida tahrirlanadi.
+const (
+	navigationReply  = "reply"
+	navigationInline = "inline"
+)
+
+// Inline navigatsiya yoqilganmi
+func inlineNavigation() bool {
+	return cfg.Navigation == navigationInline
+}
+
+// Inline rejimda callback kelgan xabar yangisini yubormasdan tahrirlanadi
+func editInPlace(ctx *Context) bool {
+	return inlineNavigation() && ctx.Callback != nil
+}
+
+// Inline "⬅️ Orqaga" tugmasi. target - qaytiladigan joy (handleNavBackCallback):
+// foydalanuvchi holati o'zgargan bo'lsa ham eski xabardagi tugma to'g'ri ishlaydi.
+func inlineBackRow(target string) []tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("⬅️ Orqaga", "nav_back:"+target))
+}
+
+// Yozuvdan qaytish joyi: teg ro'yxatidan ochilgan bo'lsa shu teg, aks holda
+// yozuv joylashgan kategoriya
+func entryBackTarget(state *UserState, section Section, title string, entry Entry) string {
+	if tag := state.TempData["tag"]; tag != "" && entry.HasTag(tag) {
+		return fmt.Sprintf("g:%s:%s", section.ID, tag)
+	}
+	path, _ := splitEntryPath(title)
+	return fmt.Sprintf("f:%s:%d", entry.ID, len(path))
+}
+
+// Inline "⬅️ Orqaga" ("nav_back:qayerga"):
+// s:bo'lim - bo'lim rollari, t:bo'lim - bo'lim teglari, g:bo'lim:teg - teg
+// yozuvlari, f:yozuv:chuqurlik - yozuv yo'lidagi kategoriya (0 - rolning o'zi).
+// Bo'sh bo'lsa - bo'limlar ro'yxati.
+func handleNavBackCallback(ctx *Context) {
+	logUserAction(ctx.User, "Orqaga qaytdi", "")
+	kind, rest, _ := strings.Cut(ctx.Arg, ":")
+	if kind == "f" {
+		id, depth, _ := strings.Cut(rest, ":")
+		showEntryFolder(ctx, id, depth)
+		return
+	}
+
+	sectionID, tag, _ := strings.Cut(rest, ":")
+	section, exists, err := store.Section(sectionID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !exists {
+		resetUserState(ctx.User.ID)
+		showTopMenu(ctx)
+		return
+	}
+	switch kind {
+	case "s":
+		showSection(ctx, section)
+	case "t":
+		showSectionTags(ctx, section)
+	case "g":
+		showTag(ctx, section, tag)
+	default:
+		resetUserState(ctx.User.ID)
+		showTopMenu(ctx)
+	}
+}
+
+// Bo'lim tugmalari inline qatorlari (har qatorda ikkitadan)
+func sectionInlineRows() [][]tgbotapi.InlineKeyboardButton {
+	sections, err := store.Sections()
+	if err != nil {
+		log.Printf("Bo'limlarni o'qishda xatolik: %v", err)
+		return nil
+	}
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i := 0; i < len(sections); i += 2 {
+		row := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(sections[i].Button(), "nav_section:"+sections[i].ID))
+		if i+1 < len(sections) {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(sections[i+1].Button(), "nav_section:"+sections[i+1].ID))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Inline rejimda bo'limlar ro'yxatiga qaytish (callback bo'lsa xabar joyida)
+func showSectionsMenu(ctx *Context) {
+	rows := sectionInlineRows()
+	text := "Bo'limni tanlang:"
+	if len(rows) == 0 {
+		text = "Hozircha bo'limlar mavjud emas."
+	}
+	sendOrEdit(ctx, editInPlace(ctx), text, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows})
+}
+
+// Eng yuqori darajaga qaytish: inline rejimda bo'limlar xabari, aks holda menyu
+func showTopMenu(ctx *Context) {
+	if editInPlace(ctx) {
+		showSectionsMenu(ctx)
+		return
+	}
+	sendMenu(ctx)
+}
+
+// Inline bo'lim tugmasi ("nav_section:bo'lim")
+func handleNavSectionCallback(ctx *Context) {
+	section, exists, err := store.Section(ctx.Arg)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !exists {
+		// Bo'lim o'chirilgan - yangilangan ro'yxatni ko'rsatamiz
+		showSectionsMenu(ctx)
+		return
+	}
+	logUserAction(ctx.User, "Bo'limga kirdi", section.Name)
+	showSection(ctx, section)
+}
+
+// Telegram callback ma'lumotining eng katta uzunligi (bayt)
+const callbackDataLimit = 64
+
+// Bo'lim ID si qo'shilgan navigatsiya callbacki ("prefiks:bo'lim:qiymat"), xuddi
+// nav_back kabi: eski xabardagi tugma foydalanuvchi holatiga bog'liq bo'lmaydi.
+// Uzun rol nomi bilan 64 baytga sig'masa, bo'lim ID siz ("prefiks:qiymat")
+// yoziladi va bo'lim joriy holatdan olinadi.
+func sectionCallback(prefix, sectionID, value string) string {
+	if data := prefix + sectionID + ":" + value; len(data) <= callbackDataLimit {
+		return data
+	}
+	return prefix + value
+}
+
+// Navigatsiya callbackidagi bo'lim (bo'sh bo'lsa - joriy holatdagi bo'lim).
+// Bo'lim topilmasa, eng yuqori darajaga qaytiladi.
+func navSection(ctx *Context, sectionID string) (Section, bool) {
+	if sectionID == "" {
+		sectionID = ctx.State.TempData["section"]
+	}
+	section, exists, err := store.Section(sectionID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return Section{}, false
+	}
+	if !exists {
+		resetUserState(ctx.User.ID)
+		showTopMenu(ctx)
+		return Section{}, false
+	}
+	return section, true
+}
+
+// sectionCallback argumentini bo'lim ID si va qiymatga ajratish
+// (rol va teglarda : belgisi bo'lmaydi)
+func splitSectionArg(arg string) (string, string) {
+	if sectionID, value, found := strings.Cut(arg, ":"); found {
+		return sectionID, value
+	}
+	return "", arg
+}
+
+// Inline rol tugmasi ("nav_role:bo'lim:rol")
+func handleNavRoleCallback(ctx *Context) {
+	sectionID, role := splitSectionArg(ctx.Arg)
+	section, ok := navSection(ctx, sectionID)
+	if !ok {
+		return
+	}
+	if !isRole(role, isAdmin(ctx.User.UserName)) {
+		showSection(ctx, section)
+		return
+	}
+	logUserAction(ctx.User, fmt.Sprintf("%s: '%s' rolini ko'rdi", section.Name, role), "")
+	showSectionRole(ctx, section, role, nil)
+}
+
+// Inline "🏷 Teglar" tugmasi ("nav_tags:bo'lim")
+func handleNavTagsCallback(ctx *Context) {
+	section, ok := navSection(ctx, ctx.Arg)
+	if !ok {
+		return
+	}
+	logUserAction(ctx.User, "Teglar ro'yxatini ochdi", section.Name)
+	showSectionTags(ctx, section)
+}
+
+// Inline teg tugmasi ("nav_tag:bo'lim:teg")
+func handleNavTagCallback(ctx *Context) {
+	sectionID, tag := splitSectionArg(ctx.Arg)
+	section, ok := navSection(ctx, sectionID)
+	if !ok {
+		return
+	}
+	logUserAction(ctx.User, fmt.Sprintf("%s: '%s' tegini ko'rdi", section.Name, tag), "")
+	showTag(ctx, section, tag)
+}
+
+// Sahifa hisoblagichi ("noop") - hech narsa qilmaydi, faqat callbackga javob beriladi
+func handleNoopCallback(ctx *Context) {}
+
+// Inline rejimda yozuv videolarini yuborish ("entry_videos:yozuv ID si").
+// Videolarni xabar ichiga joylab bo'lmaydi, shuning uchun ular alohida
+// yuboriladi va ostidan navigatsiya davom etadi.
+func handleEntryVideosCallback(ctx *Context) {
+	section, title, ok := callbackEntry(ctx)
+	if !ok {
+		return
+	}
+	entry, found, err := store.Entry(section.ID, title)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !found {
+		sendMessage(ctx.Bot, ctx.ChatID, "Bunday yozuv topilmadi.")
+		return
+	}
+
+	logUserAction(ctx.User, "Yozuv videolarini ko'rdi", entryLogRef(section.ID, title))
+	sendEntryVideos(ctx, section.ID, title, entry, entryCaption(section, title, entry))
+
+	msg := tgbotapi.NewMessage(ctx.ChatID, "Ro'yxatga qaytish uchun tugmani bosing.")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineBackRow(entryBackTarget(ctx.State, section, title, entry)))
+	outbox.Send(msg)
+}
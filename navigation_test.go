@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSectionCallback(t *testing.T) {
+	if got := sectionCallback("nav_role:", "stories", "Tank"); got != "nav_role:stories:Tank" {
+		t.Errorf("sectionCallback = %q", got)
+	}
+	// 64 baytga sig'maydigan callback bo'lim ID siz yoziladi
+	role := strings.Repeat("a", 40)
+	if got := sectionCallback("nav_role:", strings.Repeat("s", 26), role); got != "nav_role:"+role {
+		t.Errorf("uzun sectionCallback = %q", got)
+	}
+}
+
+// Inline tugmalar bo'limni o'zi bilan olib yuradi: foydalanuvchi boshqa
+// bo'limga o'tgan yoki bot qayta ishga tushib holat yo'qolgan bo'lsa ham
+// eski xabardagi tugma o'z bo'limi va ro'yxatini ochadi
+func TestInlineNavigationCallbacks(t *testing.T) {
+	tests := []struct {
+		name     string
+		section  string // joriy holatdagi bo'lim ("" - holat yo'q)
+		click    string
+		wantText []string
+	}{
+		{"boshqa bo'limdagi rol", "tutorials", "nav_role:stories:Tank", []string{"Geroylar tarixi › Tank", "Diggi"}},
+		{"eski rol tugmasi", "stories", "nav_role:Tank", []string{"Geroylar tarixi › Tank", "Diggi"}},
+		{"holatsiz teglar", "", "nav_tags:stories", []string{"qaysi teg", "nav_tag:stories:meta"}},
+		{"holatsiz teg", "", "nav_tag:stories:meta", []string{"Geroylar tarixi › #meta", "Diggi"}},
+		{"o'chirilgan bo'lim", "", "nav_role:builds:Tank", []string{"Bo'limni tanlang"}},
+		{"rol sahifa tugmasi", "", "nav_role:tutorials:Fighter", []string{"1/2", "browse_page:f:aaaa0001:0:1"}},
+		{"holatsiz sahifa", "", "browse_page:f:aaaa0001:0:1", []string{"Tutorials › Fighter", "2/2", "Hero 10"}},
+		{"holatsiz teg sahifasi", "stories", "browse_page:g:tutorials:meta:1", []string{"Tutorials › #meta", "2/2", "Hero 10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, "guest")
+			cfg.Navigation = navigationInline
+			store.SaveEntry("stories", "Diggi", testEntry("bbbb0001", "Tank"))
+			for i := 1; i <= 10; i++ {
+				store.SaveEntry("tutorials", fmt.Sprintf("Hero %02d", i), testEntry(fmt.Sprintf("aaaa%04d", i), "Fighter"))
+			}
+			if tt.section != "" {
+				getUserState(c.user.ID).TempData["section"] = tt.section
+			}
+
+			out := replies(c.click(tt.click))
+			for _, want := range tt.wantText {
+				if !strings.Contains(out, want) {
+					t.Errorf("javobda %q yo'q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestNoopCallback(t *testing.T) {
+	c := newTestClient(t, "guest")
+	got := methods(c.click("noop"))
+	if want := []string{"answerCallbackQuery"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("noop so'rovlari = %v, want %v", got, want)
+	}
+}
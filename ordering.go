@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ro'yxat belgilari
+const (
+	pinnedBadge = "📌 "
+	newBadge    = "🆕 "
+)
+
+// Yozuvlar tartibi: avval qadalganlar, keyin admin bergan tartib raqami
+// bo'yicha (raqamsizlar oxirida), qolganlari alifbo bo'yicha.
+// Xarita tartibi har safar o'zgargani uchun ro'yxatlar faqat shu orqali saralanadi.
+func entryLess(titleA string, a Entry, titleB string, b Entry) bool {
+	if a.Pinned != b.Pinned {
+		return a.Pinned
+	}
+	if a.Position != b.Position {
+		if a.Position == 0 || b.Position == 0 {
+			return b.Position == 0
+		}
+		return a.Position < b.Position
+	}
+	return titleA < titleB
+}
+
+// Yozuv nomlarini ko'rsatish tartibida saralash. names - tugma matnlari,
+// titles - ularga mos yozuv nomlari (kategoriya ichida ular farq qiladi).
+func sortEntryNames(names []string, titles map[string]string, entries map[string]Entry) {
+	sort.Slice(names, func(i, j int) bool {
+		a, b := titles[names[i]], titles[names[j]]
+		return entryLess(names[i], entries[a], names[j], entries[b])
+	})
+}
+
+// Bo'lim yozuvlari ko'rsatish tartibida
+func sortedTitles(entries map[string]Entry) []string {
+	titles := make([]string, 0, len(entries))
+	same := make(map[string]string, len(entries))
+	for title := range entries {
+		titles = append(titles, title)
+		same[title] = title
+	}
+	sortEntryNames(titles, same, entries)
+	return titles
+}
+
+// Yozuv yaqinda yaratilgan yoki yangilanganmi (new_badge_window: 0 - belgi o'chirilgan)
+func isNewEntry(entry Entry) bool {
+	if cfg.NewBadgeWindow <= 0 {
+		return false
+	}
+	changed := entry.CreatedAt
+	if entry.UpdatedAt.After(changed) {
+		changed = entry.UpdatedAt
+	}
+	return !changed.IsZero() && time.Since(changed) < cfg.NewBadgeWindow
+}
+
+// Ro'yxatdagi tugma matni belgilar bilan: "📌 🆕 Laning"
+func entryLabel(name string, entry Entry) string {
+	label := name
+	if isNewEntry(entry) {
+		label = newBadge + label
+	}
+	if entry.Pinned {
+		label = pinnedBadge + label
+	}
+	return label
+}
+
+// Yozuvni qadash yoki qadashni bekor qilish
+func handleTogglePinCallback(ctx *Context) {
+	section, title, ok := callbackEntry(ctx)
+	if !ok {
+		return
+	}
+	var pinned bool
+	err := store.UpdateEntry(section.ID, title, func(entry *Entry, exists bool) error {
+		if !exists {
+			return errNotFound
+		}
+		entry.Pinned = !entry.Pinned
+		pinned = entry.Pinned
+		return nil
+	})
+	switch {
+	case errors.Is(err, errNotFound):
+		sendMessage(ctx.Bot, ctx.ChatID, "Yozuv topilmadi.")
+		return
+	case err != nil:
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	action := "Admin: Yozuv qadaldi"
+	if !pinned {
+		action = "Admin: Yozuv qadashdan olindi"
+	}
+	logUserAction(ctx.User, action, entryLogRef(section.ID, title))
+	showManagedEntry(ctx, section, title)
+}
+
+// Yozuv tartib raqamini o'zgartirishni boshlash
+func handleUpdatePositionCallback(ctx *Context) {
+	section, title, ok := beginEntryEdit(ctx, STATE_UPDATE_POSITION)
+	if !ok {
+		return
+	}
+	entry, _, err := store.Entry(section.ID, title)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	current := "yo'q (alifbo bo'yicha)"
+	if entry.Position > 0 {
+		current = strconv.Itoa(entry.Position)
+	}
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' tartib raqami: %s\n\nYangi tartib raqamini kiriting (kichik raqamlar ro'yxat boshida). Alifbo tartibiga qaytarish uchun 0 yuboring:", title, current))
+}
+
+// Yozuvning yangi tartib raqami kiritildi
+func handleUpdatePositionInput(ctx *Context) {
+	position, err := strconv.Atoi(strings.TrimSpace(ctx.Text))
+	if err != nil || position < 0 || position > 9999 {
+		sendMessage(ctx.Bot, ctx.ChatID, "Iltimos, 0 dan 9999 gacha butun son kiriting.")
+		return
+	}
+	editEntry(ctx, "Admin: Yozuv tartibi o'zgartirildi", "'%s' tartib raqami muvaffaqiyatli yangilandi!", func(entry *Entry) {
+		entry.Position = position
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSortedTitles(t *testing.T) {
+	entries := map[string]Entry{
+		"Zed":     {},
+		"Alucard": {},
+		"Layla":   {Position: 2},
+		"Tigreal": {Position: 1},
+		"Miya":    {Pinned: true},
+		"Chou":    {Pinned: true, Position: 5},
+		"Akai":    {Pinned: true, Position: 3},
+	}
+	want := []string{"Akai", "Chou", "Miya", "Tigreal", "Layla", "Alucard", "Zed"}
+	for i := 0; i < 10; i++ { // xarita tartibi har safar boshqacha
+		if got := sortedTitles(entries); !reflect.DeepEqual(got, want) {
+			t.Fatalf("sortedTitles = %v, want %v", got, want)
+		}
+	}
+}
+
+// Kategoriya ichida tugma matni bo'yicha saralanadi, yozuv xususiyatlari esa
+// to'liq nomdan olinadi
+func TestSortEntryNames(t *testing.T) {
+	entries := map[string]Entry{
+		"Alucard / Laning": {},
+		"Alucard / Jungle": {},
+		"Alucard / Combo":  {Position: 1},
+	}
+	names := []string{"Laning", "Jungle", "Combo"}
+	titles := map[string]string{"Laning": "Alucard / Laning", "Jungle": "Alucard / Jungle", "Combo": "Alucard / Combo"}
+	sortEntryNames(names, titles, entries)
+	if want := []string{"Combo", "Jungle", "Laning"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("sortEntryNames = %v, want %v", names, want)
+	}
+}
+
+func TestEntryLabel(t *testing.T) {
+	setupTestBot(t)
+	cfg.NewBadgeWindow = 48 * time.Hour
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		entry Entry
+		want  string
+	}{
+		{"oddiy", Entry{CreatedAt: now.Add(-72 * time.Hour)}, "Laning"},
+		{"yangi", Entry{CreatedAt: now.Add(-time.Hour)}, newBadge + "Laning"},
+		{"yaqinda yangilangan", Entry{CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now.Add(-time.Hour)}, newBadge + "Laning"},
+		{"vaqtsiz (eski ma'lumot)", Entry{}, "Laning"},
+		{"qadalgan", Entry{Pinned: true}, pinnedBadge + "Laning"},
+		{"qadalgan va yangi", Entry{Pinned: true, CreatedAt: now}, pinnedBadge + newBadge + "Laning"},
+	}
+	for _, tt := range tests {
+		if got := entryLabel("Laning", tt.entry); got != tt.want {
+			t.Errorf("%s: entryLabel = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	cfg.NewBadgeWindow = 0
+	if got := entryLabel("Laning", Entry{CreatedAt: now}); strings.Contains(got, newBadge) {
+		t.Errorf("belgi o'chirilganda entryLabel = %q", got)
+	}
+}
+
+func TestUpdatePositionInput(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantPosition int
+		wantText     string
+	}{
+		{"3", 3, "muvaffaqiyatli yangilandi"},
+		{"0", 0, "muvaffaqiyatli yangilandi"},
+		{"-1", 2, "0 dan 9999 gacha"},
+		{"birinchi", 2, "0 dan 9999 gacha"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			c := newTestClient(t, "boss")
+			store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+			state := getUserState(c.user.ID)
+			state.State = STATE_UPDATE_POSITION
+			state.TempData["updateSection"] = "tutorials"
+			state.TempData["updateTitle"] = "Chichi"
+
+			out := replies(c.send(tt.input))
+			entry, _, _ := store.Entry("tutorials", "Chichi")
+			if entry.Position != tt.wantPosition {
+				t.Errorf("tartib raqami = %d, want %d", entry.Position, tt.wantPosition)
+			}
+			if !strings.Contains(out, tt.wantText) {
+				t.Errorf("javob %q, want %q", out, tt.wantText)
+			}
+		})
+	}
+}
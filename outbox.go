@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// sender - Telegramga boradigan barcha so'rovlar shu orqali yuboriladi.
+// U umumiy va har bir chat uchun alohida tezlik chegarasini saqlaydi,
+// 429 javobidagi retry_after ni hurmat qiladi va vaqtinchalik xatoliklarda
+// cheklangan marta qayta urinadi. Chaqiruvchi ishchi javob kelguncha kutadi,
+// shuning uchun bitta foydalanuvchiga xabarlar tartibi buzilmaydi.
+type sender struct {
+	bot        *tgbotapi.BotAPI
+	global     *rateLimiter
+	chats      *limiterSet
+	maxRetries int
+
+	metrics sendMetrics
+}
+
+// Yuborish statistikasi
+type sendMetrics struct {
+	sent        atomic.Int64 // muvaffaqiyatli yuborilgan
+	retried     atomic.Int64 // qayta urinishlar
+	rateLimited atomic.Int64 // 429 javoblari
+	failed      atomic.Int64 // Telegram rad etgan (qayta urinib bo'lmaydi)
+	dropped     atomic.Int64 // urinishlar tugab, yuborilmay qolgan
+}
+
+const (
+	chatBurst       = 3 // bitta chatga ketma-ket darhol yuborish mumkin bo'lgan xabarlar
+	sendBackoffBase = 500 * time.Millisecond
+	sendBackoffMax  = 30 * time.Second
+)
+
+// Global yuboruvchi (main ichida yaratiladi)
+var outbox *sender
+
+func newSender(bot *tgbotapi.BotAPI, globalRate, chatRate float64, maxRetries int) *sender {
+	return &sender{
+		bot:        bot,
+		global:     newRateLimiter(globalRate, int(globalRate)),
+		chats:      newLimiterSet(chatRate, chatBurst),
+		maxRetries: maxRetries,
+	}
+}
+
+// Xabar yuborish (bot.Send o'rniga)
+func (s *sender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var message tgbotapi.Message
+	err := s.do(c, func() (err error) {
+		message, err = s.bot.Send(c)
+		return err
+	})
+	return message, err
+}
+
+// Xabarni nusxalash (bot.CopyMessage o'rniga)
+func (s *sender) CopyMessage(config tgbotapi.CopyMessageConfig) (tgbotapi.MessageID, error) {
+	var messageID tgbotapi.MessageID
+	err := s.do(config, func() error {
+		resp, err := s.bot.Request(config)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(resp.Result, &messageID)
+	})
+	return messageID, err
+}
+
+// Albom yuborish (bot.SendMediaGroup o'rniga)
+func (s *sender) SendMediaGroup(config tgbotapi.MediaGroupConfig) ([]tgbotapi.Message, error) {
+	var messages []tgbotapi.Message
+	err := s.do(config, func() (err error) {
+		messages, err = s.bot.SendMediaGroup(config)
+		return err
+	})
+	return messages, err
+}
+
+// Xabar qaytarmaydigan so'rovlar (callback javobi, tahrirlash va h.k.)
+func (s *sender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	var resp *tgbotapi.APIResponse
+	err := s.do(c, func() (err error) {
+		resp, err = s.bot.Request(c)
+		return err
+	})
+	return resp, err
+}
+
+// So'rovni chegaralar va qayta urinishlar bilan bajarish
+func (s *sender) do(c tgbotapi.Chattable, call func() error) error {
+	chatID := chattableChatID(c)
+	var err error
+	for attempt := 0; ; attempt++ {
+		if chatID != 0 {
+			s.chats.get(chatID).wait()
+		}
+		s.global.wait()
+
+		err = call()
+		if err == nil {
+			s.metrics.sent.Add(1)
+			return nil
+		}
+
+		delay, retryable := s.retryDelay(err, attempt)
+		if !retryable {
+			s.metrics.failed.Add(1)
+			log.Printf("So'rov rad etildi (%T, chat %d): %v", c, chatID, err)
+			return err
+		}
+		if attempt >= s.maxRetries {
+			break
+		}
+
+		s.metrics.retried.Add(1)
+		log.Printf("So'rov %v dan keyin qayta yuboriladi (%T, chat %d): %v", delay, c, chatID, err)
+		time.Sleep(delay)
+	}
+
+	s.metrics.dropped.Add(1)
+	log.Printf("So'rov %d marta urinishdan keyin yuborilmadi (%T, chat %d): %v", s.maxRetries+1, c, chatID, err)
+	return err
+}
+
+// Xatolik bo'yicha qayta urinish kerakmi va qancha kutish kerakligini aniqlash
+func (s *sender) retryDelay(err error, attempt int) (time.Duration, bool) {
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Code == 429:
+			s.metrics.rateLimited.Add(1)
+			if apiErr.RetryAfter > 0 {
+				return time.Duration(apiErr.RetryAfter) * time.Second, true
+			}
+			return backoff(attempt), true
+		case apiErr.Code >= 500:
+			return backoff(attempt), true
+		default:
+			// 400, 403 kabi xatoliklar qayta urinish bilan tuzalmaydi
+			return 0, false
+		}
+	}
+	// Tarmoq xatoliklari
+	return backoff(attempt), true
+}
+
+// Eksponensial kutish vaqti
+func backoff(attempt int) time.Duration {
+	delay := sendBackoffBase << attempt
+	if delay <= 0 || delay > sendBackoffMax {
+		return sendBackoffMax
+	}
+	return delay
+}
+
+// Yuborish statistikasini matn ko'rinishida olish
+func (s *sender) summary() string {
+	return fmt.Sprintf("📤 Yuborish: %d ta yuborildi, %d ta qayta urinish (%d tasi 429), %d ta rad etildi, %d ta yo'qotildi",
+		s.metrics.sent.Load(), s.metrics.retried.Load(), s.metrics.rateLimited.Load(),
+		s.metrics.failed.Load(), s.metrics.dropped.Load())
+}
+
+// So'rov qaysi chatga yuborilishini aniqlash (0 - noma'lum, faqat umumiy chegara)
+func chattableChatID(c tgbotapi.Chattable) int64 {
+	switch config := c.(type) {
+	case tgbotapi.MessageConfig:
+		return config.ChatID
+	case tgbotapi.DocumentConfig:
+		return config.ChatID
+	case tgbotapi.VideoConfig:
+		return config.ChatID
+	case tgbotapi.PhotoConfig:
+		return config.ChatID
+	case tgbotapi.ForwardConfig:
+		return config.ChatID
+	case tgbotapi.CopyMessageConfig:
+		return config.ChatID
+	case tgbotapi.MediaGroupConfig:
+		return config.ChatID
+	case tgbotapi.EditMessageTextConfig:
+		return config.ChatID
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return config.ChatID
+	case tgbotapi.DeleteMessageConfig:
+		return config.ChatID
+	}
+	return 0
+}
+
+// rateLimiter - token chelagi: sekundiga rate ta, ketma-ket burst tagacha
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		interval: time.Duration(float64(time.Second) / rate),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// Navbatdagi token uchun joy band qilib, kerak bo'lsa kutish
+func (l *rateLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens * float64(l.interval))
+	}
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// Token bo'lsa uni olish, bo'lmasa kutmasdan false qaytarish
+func (l *rateLimiter) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Oxirgi ishlatilgandan beri o'tgan vaqt
+func (l *rateLimiter) idleFor() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return time.Since(l.last)
+}
+
+// limiterSet - har bir kalit (chat yoki foydalanuvchi) uchun alohida chegara
+type limiterSet struct {
+	mu    sync.Mutex
+	rate  float64
+	burst int
+	items map[int64]*rateLimiter
+}
+
+const (
+	limiterIdle   = time.Minute // shuncha ishlatilmagan chegaralar o'chiriladi
+	limiterPrunes = 1000        // chegaralar soni shundan oshsa tozalanadi
+)
+
+func newLimiterSet(rate float64, burst int) *limiterSet {
+	return &limiterSet{rate: rate, burst: burst, items: make(map[int64]*rateLimiter)}
+}
+
+// Kalit uchun chegarani olish (kerak bo'lsa yaratish)
+func (ls *limiterSet) get(key int64) *rateLimiter {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	limiter, exists := ls.items[key]
+	if !exists {
+		if len(ls.items) >= limiterPrunes {
+			for id, l := range ls.items {
+				if l.idleFor() > limiterIdle {
+					delete(ls.items, id)
+				}
+			}
+		}
+		limiter = newRateLimiter(ls.rate, ls.burst)
+		ls.items[key] = limiter
+	}
+	return limiter
+}
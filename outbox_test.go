@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestSenderRetryDelay(t *testing.T) {
+	s := &sender{}
+	tests := []struct {
+		name          string
+		err           error
+		attempt       int
+		wantDelay     time.Duration
+		wantRetryable bool
+	}{
+		{"429 retry_after bilan", &tgbotapi.Error{Code: 429, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 3}}, 0, 3 * time.Second, true},
+		{"429 retry_after siz", &tgbotapi.Error{Code: 429}, 1, 2 * sendBackoffBase, true},
+		{"server xatoligi", &tgbotapi.Error{Code: 502}, 2, 4 * sendBackoffBase, true},
+		{"taqiqlangan", &tgbotapi.Error{Code: 403}, 0, 0, false},
+		{"noto'g'ri so'rov", &tgbotapi.Error{Code: 400}, 0, 0, false},
+		{"tarmoq xatoligi", errors.New("connection reset"), 0, sendBackoffBase, true},
+		{"kutish chegarasi", errors.New("timeout"), 40, sendBackoffMax, true},
+	}
+	for _, tt := range tests {
+		delay, retryable := s.retryDelay(tt.err, tt.attempt)
+		if delay != tt.wantDelay || retryable != tt.wantRetryable {
+			t.Errorf("%s: retryDelay = (%v, %v), want (%v, %v)", tt.name, delay, retryable, tt.wantDelay, tt.wantRetryable)
+		}
+	}
+}
+
+func TestSenderRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int    // shuncha so'rov xatolik bilan javob oladi
+		response   string // xatolik javobi
+		maxRetries int
+		wantCalls  int
+		wantErr    bool
+		minElapsed time.Duration
+	}{
+		{
+			name:       "429 da retry_after kutiladi",
+			failures:   1,
+			response:   `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`,
+			maxRetries: 3,
+			wantCalls:  2,
+			minElapsed: time.Second,
+		},
+		{
+			name:       "400 qayta yuborilmaydi",
+			failures:   1,
+			response:   `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`,
+			maxRetries: 3,
+			wantCalls:  1,
+			wantErr:    true,
+		},
+		{
+			name:       "urinishlar tugasa xatolik qaytadi",
+			failures:   5,
+			response:   `{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+			maxRetries: 1,
+			wantCalls:  2,
+			wantErr:    true,
+			minElapsed: sendBackoffBase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, fake := setupTestBot(t)
+			var calls atomic.Int32
+			fake.respond = func(method string, form url.Values) string {
+				if method != "sendMessage" {
+					return ""
+				}
+				if calls.Add(1) <= int32(tt.failures) {
+					return tt.response
+				}
+				return ""
+			}
+			s := newSender(bot, 1000, 1000, tt.maxRetries)
+
+			start := time.Now()
+			_, err := s.Send(tgbotapi.NewMessage(42, "salom"))
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := int(calls.Load()); got != tt.wantCalls {
+				t.Errorf("so'rovlar soni = %d, want %d", got, tt.wantCalls)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("kutish %v, kamida %v bo'lishi kerak", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+// Bitta chatga burst dan ortiq xabar chat tezligida yuboriladi
+func TestSenderChatRate(t *testing.T) {
+	bot, _ := setupTestBot(t)
+	s := newSender(bot, 1000, 20, 0)
+
+	start := time.Now()
+	for i := 0; i < chatBurst+2; i++ {
+		s.Send(tgbotapi.NewMessage(42, "salom"))
+	}
+	// Burst tugagach har bir xabar 1/20 sekund kutadi
+	if elapsed := time.Since(start); elapsed < 2*time.Second/20-10*time.Millisecond {
+		t.Errorf("%d ta xabar %v da yuborildi - chat chegarasi ishlamadi", chatBurst+2, elapsed)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Bir sahifadagi elementlar soni
+const (
+	userPageSize  = 8
+	adminPageSize = 10
+)
+
+// Sahifa chegaralarini hisoblash. Sahifa raqami [0, pages) oralig'iga keltiriladi,
+// shuning uchun ro'yxat qisqargandan keyin bosilgan eski tugma ham ishlaydi.
+func pageBounds(total, page, size int) (start, end, current, pages int) {
+	pages = (total + size - 1) / size
+	if pages == 0 {
+		pages = 1
+	}
+	current = page
+	if current >= pages {
+		current = pages - 1
+	}
+	if current < 0 {
+		current = 0
+	}
+	start = current * size
+	end = start + size
+	if end > total {
+		end = total
+	}
+	return start, end, current, pages
+}
+
+// Sahifalash qatori "◀️ 2/5 ▶️". Bitta sahifa bo'lsa nil qaytaradi.
+// prefix - callback boshlanishi, unga sahifa raqami qo'shiladi.
+func pagerRow(prefix string, page, pages int) []tgbotapi.InlineKeyboardButton {
+	if pages <= 1 {
+		return nil
+	}
+	var row []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("◀️", prefix+strconv.Itoa(page-1)))
+	}
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d/%d", page+1, pages), "noop"))
+	if page < pages-1 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("▶️", prefix+strconv.Itoa(page+1)))
+	}
+	return row
+}
+
+// Inline ro'yxatni yuborish yoki (edit bo'lsa) callback kelgan xabarni joyida tahrirlash
+func sendOrEdit(ctx *Context, edit bool, text string, markup tgbotapi.InlineKeyboardMarkup) {
+	if edit && ctx.Callback != nil && ctx.Callback.Message != nil {
+		config := tgbotapi.NewEditMessageText(ctx.ChatID, ctx.Callback.Message.MessageID, text)
+		if len(markup.InlineKeyboard) > 0 {
+			config.ReplyMarkup = &markup
+		}
+		outbox.Request(config)
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	if len(markup.InlineKeyboard) > 0 {
+		msg.ReplyMarkup = markup
+	}
+	outbox.Send(msg)
+}
+
+// Callback argumentidagi raqam (noto'g'ri bo'lsa 0)
+func callbackNumber(value string) int {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0
+	}
+	return number
+}
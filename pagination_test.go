@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		name                            string
+		total, page, size               int
+		start, end, current, totalPages int
+	}{
+		{"bo'sh ro'yxat", 0, 0, 8, 0, 0, 0, 1},
+		{"birinchi sahifa", 20, 0, 8, 0, 8, 0, 3},
+		{"o'rta sahifa", 20, 1, 8, 8, 16, 1, 3},
+		{"oxirgi to'liq bo'lmagan sahifa", 20, 2, 8, 16, 20, 2, 3},
+		{"aniq bo'linadi", 16, 1, 8, 8, 16, 1, 2},
+		{"qisqargan ro'yxatdagi eski sahifa", 9, 5, 8, 8, 9, 1, 2},
+		{"manfiy sahifa", 9, -1, 8, 0, 8, 0, 2},
+	}
+	for _, tt := range tests {
+		start, end, current, pages := pageBounds(tt.total, tt.page, tt.size)
+		if start != tt.start || end != tt.end || current != tt.current || pages != tt.totalPages {
+			t.Errorf("%s: pageBounds(%d, %d, %d) = (%d, %d, %d, %d), want (%d, %d, %d, %d)", tt.name,
+				tt.total, tt.page, tt.size, start, end, current, pages, tt.start, tt.end, tt.current, tt.totalPages)
+		}
+	}
+}
+
+func TestPagerRow(t *testing.T) {
+	tests := []struct {
+		page, pages int
+		want        []string // "matn=callback"
+	}{
+		{0, 1, nil},
+		{0, 3, []string{"1/3=noop", "▶️=p:1"}},
+		{1, 3, []string{"◀️=p:0", "2/3=noop", "▶️=p:2"}},
+		{2, 3, []string{"◀️=p:1", "3/3=noop"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, button := range pagerRow("p:", tt.page, tt.pages) {
+			got = append(got, button.Text+"="+*button.CallbackData)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("pagerRow(%d, %d) = %v, want %v", tt.page, tt.pages, got, tt.want)
+		}
+	}
+}
+
+func TestCallbackNumber(t *testing.T) {
+	tests := map[string]int{"3": 3, "0": 0, "-2": 0, "abc": 0, "": 0}
+	for value, want := range tests {
+		if got := callbackNumber(value); got != want {
+			t.Errorf("callbackNumber(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
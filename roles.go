@@ -0,0 +1,403 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Role - yozuvlarni guruhlash uchun rol (masalan, "Tank" yoki "Mage").
+// Rollar ro'yxati admin paneldan boshqariladi; yashirin rollar foydalanuvchi
+// klaviaturasida ko'rinmaydi, lekin ularning yozuvlari saqlanib qoladi.
+type Role struct {
+	Name     string `json:"name"`
+	Position int    `json:"position"` // klaviaturadagi tartib raqami
+	Hidden   bool   `json:"hidden,omitempty"`
+}
+
+// Yangi o'rnatishda yaratiladigan rollar
+func defaultRoles() []Role {
+	return []Role{
+		{Name: "Marksman/ADK", Position: 1},
+		{Name: "Tank", Position: 2},
+		{Name: "Fighter", Position: 3},
+		{Name: "Assassin", Position: 4},
+		{Name: "Support", Position: 5},
+		{Name: "Mage", Position: 6},
+	}
+}
+
+// Rollarni klaviaturadagi tartibda saralash
+func sortRoles(roles []Role) {
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].Position != roles[j].Position {
+			return roles[i].Position < roles[j].Position
+		}
+		return roles[i].Name < roles[j].Name
+	})
+}
+
+// Rol nomlari ro'yxati (includeHidden bo'lmasa yashirin rollarsiz)
+func roleNames(includeHidden bool) []string {
+	roles, err := store.Roles()
+	if err != nil {
+		log.Printf("Rollarni o'qishda xatolik: %v", err)
+		return nil
+	}
+	var names []string
+	for _, role := range roles {
+		if includeHidden || !role.Hidden {
+			names = append(names, role.Name)
+		}
+	}
+	return names
+}
+
+// Nom bo'yicha rolni topish
+func findRole(name string) (Role, bool) {
+	roles, err := store.Roles()
+	if err != nil {
+		log.Printf("Rollarni o'qishda xatolik: %v", err)
+		return Role{}, false
+	}
+	for _, role := range roles {
+		if role.Name == name {
+			return role, true
+		}
+	}
+	return Role{}, false
+}
+
+// Matn rol nomi ekanligini tekshirish (includeHidden bo'lmasa yashirin rollar hisobga olinmaydi)
+func isRole(value string, includeHidden bool) bool {
+	role, exists := findRole(value)
+	return exists && (includeHidden || !role.Hidden)
+}
+
+// Rol tanlash klaviaturasi (oxiriga qo'shimcha qatorlar qo'shiladi)
+func roleKeyboard(roles []string, extra ...[]tgbotapi.KeyboardButton) tgbotapi.ReplyKeyboardMarkup {
+	var rows [][]tgbotapi.KeyboardButton
+	for i := 0; i < len(roles); i += 2 {
+		row := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(roles[i]))
+		if i+1 < len(roles) {
+			row = append(row, tgbotapi.NewKeyboardButton(roles[i+1]))
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, extra...)
+
+	keyboard := tgbotapi.NewReplyKeyboard(rows...)
+	keyboard.ResizeKeyboard = true
+	return keyboard
+}
+
+// Har bir rol nechta yozuvda ishlatilganini hisoblash (barcha bo'limlar bo'yicha)
+func roleUsage() (map[string]int, error) {
+	sections, err := store.Sections()
+	if err != nil {
+		return nil, err
+	}
+	usage := make(map[string]int)
+	for _, section := range sections {
+		entries, err := store.Entries(section.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			for _, role := range entry.Roles {
+				usage[role]++
+			}
+		}
+	}
+	return usage, nil
+}
+
+// Rol nomini tekshirish. Nom callback ma'lumotiga sig'ishi, bo'lim tugmalari
+// bilan to'qnashmasligi va boshqa rollardan farq qilishi kerak.
+func validateRoleName(value, except string) (string, error) {
+	if value == "" {
+		return "", errors.New("Iltimos, matn ko'rinishida nom kiriting.")
+	}
+	if strings.HasPrefix(value, "/") {
+		return "", errors.New("Nom / belgisi bilan boshlanmasligi kerak.")
+	}
+	if strings.Contains(value, ":") {
+		return "", errors.New("Rol nomida : belgisi bo'lmasligi kerak.")
+	}
+	if len(value) > 40 {
+		return "", errors.New("Rol nomi juda uzun.")
+	}
+	if _, exists := sectionByButton(value); exists {
+		return "", errors.New("Rol nomi bo'lim nomi bilan bir xil bo'lmasligi kerak.")
+	}
+	if isReservedText(value) {
+		return "", errors.New("Bu nom menyu tugmasi bilan bir xil. Boshqa nom kiriting.")
+	}
+	roles, err := store.Roles()
+	if err != nil {
+		return "", err
+	}
+	for _, role := range roles {
+		if role.Name != except && strings.EqualFold(role.Name, value) {
+			return "", errors.New("Bunday nomli rol allaqachon mavjud.")
+		}
+	}
+	return value, nil
+}
+
+// Callback ma'lumotidagi rolni topish
+func callbackRole(ctx *Context) (Role, bool) {
+	if ctx.Arg == "" {
+		return Role{}, false
+	}
+	role, exists := findRole(ctx.Arg)
+	if !exists {
+		sendMessage(ctx.Bot, ctx.ChatID, "Rol topilmadi.")
+		return Role{}, false
+	}
+	return role, true
+}
+
+// "🎮 Rollar" tugmasi
+func handleRolesAdminButton(ctx *Context) {
+	logUserAction(ctx.User, "Admin: Rollar ro'yxatini so'radi", "")
+	showRolesForAdmin(ctx)
+}
+
+// Admin uchun rollarni boshqarish menyusini ko'rsatish
+func showRolesForAdmin(ctx *Context) {
+	roles, err := store.Roles()
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	usage, err := roleUsage()
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	for i, role := range roles {
+		toggle := tgbotapi.NewInlineKeyboardButtonData("🙈 Yashirish", "toggle_role:"+role.Name)
+		if role.Hidden {
+			toggle = tgbotapi.NewInlineKeyboardButtonData("👁 Ko'rsatish", "toggle_role:"+role.Name)
+		}
+		row := tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Nom", "rename_role:"+role.Name),
+		)
+		if i > 0 {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData("⬆️ Yuqoriga", "move_role:"+role.Name))
+		}
+		row = append(row, toggle, tgbotapi.NewInlineKeyboardButtonData("❌ O'chirish", "delete_role:"+role.Name))
+
+		text := fmt.Sprintf("%d. %s — %d ta yozuv", i+1, role.Name, usage[role.Name])
+		if role.Hidden {
+			text += " (yashirin)"
+		}
+		msg := tgbotapi.NewMessage(ctx.ChatID, text)
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+		outbox.Send(msg)
+	}
+
+	text := "Yangi rol qo'shish uchun tugmani bosing."
+	if len(roles) == 0 {
+		text = "Hozircha rollar mavjud emas. " + text
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Yangi rol", "add_role"),
+		),
+	)
+	outbox.Send(msg)
+}
+
+// Yangi rol qo'shishni boshlash
+func handleAddRoleCallback(ctx *Context) {
+	ctx.State.State = STATE_ADD_ROLE
+	logUserAction(ctx.User, "Admin: Yangi rol qo'shish boshlandi", "")
+	sendMessage(ctx.Bot, ctx.ChatID, "Yangi rol nomini kiriting (masalan, Jungler):")
+}
+
+// Yangi rol nomi kiritildi - rol ro'yxat oxiriga qo'shiladi
+func handleAddRoleInput(ctx *Context) {
+	name, err := validateRoleName(strings.TrimSpace(ctx.Text), "")
+	if err != nil {
+		sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+		return
+	}
+	roles, err := store.Roles()
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	position := 1
+	for _, role := range roles {
+		if role.Position >= position {
+			position = role.Position + 1
+		}
+	}
+
+	if err := store.SaveRole(Role{Name: name, Position: position}); err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	logUserAction(ctx.User, "Admin: Yangi rol qo'shildi", name)
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("✅ '%s' roli qo'shildi.", name))
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	resetUserState(ctx.User.ID)
+}
+
+// Rol nomini o'zgartirishni boshlash
+func handleRenameRoleCallback(ctx *Context) {
+	role, ok := callbackRole(ctx)
+	if !ok {
+		return
+	}
+	ctx.State.State = STATE_RENAME_ROLE
+	ctx.State.TempData["updateTitle"] = role.Name
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' roli uchun yangi nom kiriting. Shu roldagi barcha yozuvlar ham yangilanadi:", role.Name))
+}
+
+// Rolning yangi nomi kiritildi
+func handleRenameRoleInput(ctx *Context) {
+	oldName := ctx.State.TempData["updateTitle"]
+	name, err := validateRoleName(strings.TrimSpace(ctx.Text), oldName)
+	if err != nil {
+		sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+		return
+	}
+
+	err = store.RenameRole(oldName, name)
+	if errors.Is(err, errNotFound) {
+		sendMessage(ctx.Bot, ctx.ChatID, "Rol topilmadi.")
+	} else if errors.Is(err, errRoleExists) {
+		sendMessage(ctx.Bot, ctx.ChatID, "Bunday nomli rol allaqachon mavjud. Boshqa nom kiriting yoki /cancel yuboring.")
+		return
+	} else if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	} else {
+		logUserAction(ctx.User, "Admin: Rol nomi o'zgartirildi", fmt.Sprintf("%s -> %s", oldName, name))
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("✅ Rol nomi o'zgartirildi: %s", name))
+	}
+
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	resetUserState(ctx.User.ID)
+}
+
+// Rolni klaviaturada bir pog'ona yuqoriga ko'tarish
+func handleMoveRoleCallback(ctx *Context) {
+	role, ok := callbackRole(ctx)
+	if !ok {
+		return
+	}
+	roles, err := store.Roles()
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	for i := 1; i < len(roles); i++ {
+		if roles[i].Name != role.Name {
+			continue
+		}
+		roles[i-1], roles[i] = roles[i], roles[i-1]
+		for position := range roles {
+			if roles[position].Position == position+1 {
+				continue
+			}
+			roles[position].Position = position + 1
+			if err := store.SaveRole(roles[position]); err != nil {
+				reportStoreError(ctx.Bot, ctx.ChatID, err)
+				return
+			}
+		}
+		logUserAction(ctx.User, "Admin: Rollar tartibi o'zgartirildi", role.Name)
+		break
+	}
+
+	showRolesForAdmin(ctx)
+}
+
+// Rolni foydalanuvchilardan yashirish yoki qayta ko'rsatish
+func handleToggleRoleCallback(ctx *Context) {
+	role, ok := callbackRole(ctx)
+	if !ok {
+		return
+	}
+	role.Hidden = !role.Hidden
+	if err := store.SaveRole(role); err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	if role.Hidden {
+		logUserAction(ctx.User, "Admin: Rol yashirildi", role.Name)
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("🙈 '%s' roli foydalanuvchilardan yashirildi.", role.Name))
+	} else {
+		logUserAction(ctx.User, "Admin: Rol ko'rsatildi", role.Name)
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("👁 '%s' roli yana ko'rinadi.", role.Name))
+	}
+	showRolesForAdmin(ctx)
+}
+
+// Rolni o'chirishni tasdiqlash so'rovi. Ishlatilayotgan rol o'chirilmaydi,
+// aks holda uning yozuvlari hech qaysi klaviaturadan topilmay qoladi.
+func handleDeleteRoleCallback(ctx *Context) {
+	role, ok := callbackRole(ctx)
+	if !ok {
+		return
+	}
+	usage, err := roleUsage()
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if usage[role.Name] > 0 {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' roli %d ta yozuvda ishlatilmoqda, shuning uchun uni o'chirib bo'lmaydi. Rol nomini o'zgartiring yoki uni yashiring.", role.Name, usage[role.Name]))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("'%s' rolini o'chirishni tasdiqlaysizmi?", role.Name))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Ha, o'chirish", "confirm_delete_role:"+role.Name),
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Bekor qilish", "cancel_delete"),
+		),
+	)
+	outbox.Send(msg)
+}
+
+// Rolni o'chirish
+func handleConfirmDeleteRoleCallback(ctx *Context) {
+	role, ok := callbackRole(ctx)
+	if !ok {
+		return
+	}
+	// Tasdiqlash oralig'ida rolga yozuv qo'shilgan bo'lishi mumkin
+	usage, err := roleUsage()
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if usage[role.Name] > 0 {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' roli yozuvlarda ishlatilmoqda, shuning uchun uni o'chirib bo'lmaydi.", role.Name))
+		return
+	}
+
+	if err := store.DeleteRole(role.Name); err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	logUserAction(ctx.User, "Admin: Rol o'chirildi", role.Name)
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' roli o'chirildi.", role.Name))
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	resetUserState(ctx.User.ID)
+}
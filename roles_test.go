@@ -0,0 +1,102 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateRoleName(t *testing.T) {
+	setupTestBot(t)
+	newBotRouter()
+
+	tests := []struct {
+		name, value, except string
+		valid               bool
+	}{
+		{"yangi rol", "Jungler", "", true},
+		{"bo'sh", "", "", false},
+		{"buyruqqa o'xshash", "/tank", "", false},
+		{"ikki nuqta", "Tank:2", "", false},
+		{"juda uzun", strings.Repeat("a", 41), "", false},
+		{"bo'lim nomi", "Tutorials", "", false},
+		{"bo'lim tugmasi", "📚 Tutorials", "", false},
+		{"menyu tugmasi", "Rollar", "", false},
+		{"mavjud rol", "tank", "", false},
+		{"o'z nomini saqlash", "Tank", "Tank", true},
+	}
+	for _, tt := range tests {
+		_, err := validateRoleName(tt.value, tt.except)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: validateRoleName(%q) xatoligi = %v, valid %v", tt.name, tt.value, err, tt.valid)
+		}
+	}
+}
+
+func TestRoleAdmin(t *testing.T) {
+	tests := []struct {
+		name      string
+		click     string
+		wantRoles []string // click dan keyingi ko'rinadigan rollar tartibi
+		wantText  string
+	}{
+		{
+			name:      "rolni yuqoriga ko'tarish",
+			click:     "move_role:Fighter",
+			wantRoles: []string{"Marksman/ADK", "Fighter", "Tank", "Assassin", "Support", "Mage"},
+		},
+		{
+			name:      "birinchi rol joyida qoladi",
+			click:     "move_role:Marksman/ADK",
+			wantRoles: []string{"Marksman/ADK", "Tank", "Fighter", "Assassin", "Support", "Mage"},
+		},
+		{
+			name:      "rolni yashirish",
+			click:     "toggle_role:Mage",
+			wantRoles: []string{"Marksman/ADK", "Tank", "Fighter", "Assassin", "Support"},
+			wantText:  "yashirildi",
+		},
+		{
+			name:      "ishlatilayotgan rol o'chirilmaydi",
+			click:     "confirm_delete_role:Tank",
+			wantRoles: []string{"Marksman/ADK", "Tank", "Fighter", "Assassin", "Support", "Mage"},
+			wantText:  "ishlatilmoqda",
+		},
+		{
+			name:      "bo'sh rolni o'chirish",
+			click:     "confirm_delete_role:Support",
+			wantRoles: []string{"Marksman/ADK", "Tank", "Fighter", "Assassin", "Mage"},
+			wantText:  "o'chirildi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, "boss")
+			store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Tank"))
+
+			out := replies(c.click(tt.click))
+			if got := roleNames(false); !reflect.DeepEqual(got, tt.wantRoles) {
+				t.Errorf("rollar = %v, want %v", got, tt.wantRoles)
+			}
+			if !strings.Contains(out, tt.wantText) {
+				t.Errorf("javob %q, want %q", out, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestRoleUsage(t *testing.T) {
+	setupTestBot(t)
+	store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Tank", "Fighter"))
+	store.SaveEntry("stories", "Diggi", testEntry("aaaa0002", "Tank"))
+
+	usage, err := roleUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"Tank": 2, "Fighter": 1}
+	if !reflect.DeepEqual(usage, want) {
+		t.Errorf("roleUsage = %v, want %v", usage, want)
+	}
+}
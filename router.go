@@ -0,0 +1,177 @@
+package main
+
+import (
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Context - handlerga beriladigan yangilanish ma'lumotlari
+type Context struct {
+	Bot      *tgbotapi.BotAPI
+	Update   tgbotapi.Update
+	Message  *tgbotapi.Message       // oddiy xabar bo'lsa
+	Callback *tgbotapi.CallbackQuery // inline tugma bosilgan bo'lsa
+	User     *tgbotapi.User
+	ChatID   int64
+	State    *UserState
+	Text     string // xabar matni
+	Arg      string // callback ma'lumotida ":" dan keyingi qism
+}
+
+// handlerFunc - routerga ro'yxatdan o'tkaziladigan handler
+type handlerFunc func(ctx *Context)
+
+// route - ro'yxatdan o'tgan handler va uning cheklovlari
+type route struct {
+	handler   handlerFunc
+	adminOnly bool
+}
+
+// routeOption - ro'yxatdan o'tkazishda qo'shimcha sozlama
+type routeOption func(r *route)
+
+// Handler faqat adminlar uchun
+func adminOnly(r *route) {
+	r.adminOnly = true
+}
+
+// router - yangilanishlarni buyruq, tugma matni, callback prefiksi yoki
+// foydalanuvchi holati bo'yicha handlerlarga yo'naltiradi.
+// Xabarlar uchun tartib: buyruq -> tugma matni -> holat -> fallback.
+type router struct {
+	commands  map[string]route
+	texts     map[string]route
+	callbacks map[string]route
+	states    map[string]route
+
+	unknownCommand handlerFunc
+	fallback       handlerFunc
+}
+
+func newRouter() *router {
+	return &router{
+		commands:  make(map[string]route),
+		texts:     make(map[string]route),
+		callbacks: make(map[string]route),
+		states:    make(map[string]route),
+	}
+}
+
+func newRoute(handler handlerFunc, options []routeOption) route {
+	r := route{handler: handler}
+	for _, option := range options {
+		option(&r)
+	}
+	return r
+}
+
+// /command buyrug'i uchun handler
+func (r *router) Command(command string, handler handlerFunc, options ...routeOption) {
+	r.commands[command] = newRoute(handler, options)
+}
+
+// Klaviatura tugmasi matni uchun handler
+func (r *router) Text(text string, handler handlerFunc, options ...routeOption) {
+	r.texts[text] = newRoute(handler, options)
+	reservedTexts[text] = true
+}
+
+// r.Text orqali ro'yxatdan o'tgan tugma matnlari. Matnli routelar bo'lim va
+// rol tugmalaridan oldin tekshiriladi, shuning uchun bunday nomli bo'lim yoki
+// rol hech qachon ochilmaydi.
+var reservedTexts = make(map[string]bool)
+
+// Nom biror tugma matni bilan to'qnashadimi: to'liq mos kelsa yoki emoji
+// qo'shilganda tugma matniga aylanib qolsa ("⬅️ Orqaga" va "Orqaga")
+func isReservedText(name string) bool {
+	for text := range reservedTexts {
+		if strings.EqualFold(text, name) || strings.HasSuffix(strings.ToLower(text), " "+strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// "prefix:argument" ko'rinishidagi callback uchun handler
+func (r *router) Callback(prefix string, handler handlerFunc, options ...routeOption) {
+	r.callbacks[prefix] = newRoute(handler, options)
+}
+
+// Foydalanuvchi shu holatda bo'lganda keladigan xabarlar uchun handler
+func (r *router) State(state string, handler handlerFunc, options ...routeOption) {
+	r.states[state] = newRoute(handler, options)
+}
+
+// Noma'lum buyruq uchun handler
+func (r *router) UnknownCommand(handler handlerFunc) {
+	r.unknownCommand = handler
+}
+
+// Hech narsaga mos kelmagan xabarlar uchun handler
+func (r *router) Fallback(handler handlerFunc) {
+	r.fallback = handler
+}
+
+// Yangilanishga mos handlerni topish
+func (r *router) match(update tgbotapi.Update, state *UserState) (route, bool) {
+	if callback := update.CallbackQuery; callback != nil {
+		found, exists := r.callbacks[callbackAction(callback)]
+		return found, exists
+	}
+
+	message := update.Message
+	if message == nil {
+		return route{}, false
+	}
+	if message.IsCommand() {
+		if found, exists := r.commands[message.Command()]; exists {
+			return found, true
+		}
+		return route{handler: r.unknownCommand}, r.unknownCommand != nil
+	}
+	if found, exists := r.texts[message.Text]; exists {
+		return found, true
+	}
+	if state != nil {
+		if found, exists := r.states[state.State]; exists {
+			return found, true
+		}
+	}
+	return route{handler: r.fallback}, r.fallback != nil
+}
+
+// Holat admin jarayoniga tegishlimi
+func (r *router) isAdminState(state string) bool {
+	return r.states[state].adminOnly
+}
+
+// Yangilanishni mos handlerga yuborish
+func (r *router) Handle(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+	user := updateUser(update)
+	if user == nil {
+		return
+	}
+
+	ctx := &Context{
+		Bot:    bot,
+		Update: update,
+		User:   user,
+		ChatID: updateChatID(update),
+		State:  getUserState(user.ID),
+	}
+	if update.CallbackQuery != nil {
+		ctx.Callback = update.CallbackQuery
+		_, ctx.Arg, _ = strings.Cut(update.CallbackQuery.Data, ":")
+
+		// Tugmadagi "yuklanmoqda" belgisini o'chirish
+		outbox.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, ""))
+	} else {
+		ctx.Message = update.Message
+		ctx.Text = update.Message.Text
+	}
+
+	if found, exists := r.match(update, ctx.State); exists {
+		found.handler(ctx)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func commandUpdate(userID int64, text string) tgbotapi.Update {
+	update := messageUpdate(userID, text)
+	command, _, _ := strings.Cut(text, " ")
+	update.Message.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Length: len(command)}}
+	return update
+}
+
+func callbackUpdate(userID int64, data string) tgbotapi.Update {
+	return tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: userID}},
+		Data:    data,
+	}}
+}
+
+func TestRouterDispatch(t *testing.T) {
+	var called, arg string
+	handler := func(name string) handlerFunc {
+		return func(ctx *Context) {
+			called, arg = name, ctx.Arg
+		}
+	}
+	r := newRouter()
+	r.Command("start", handler("command"))
+	r.Text("🧪 Sinov tugmasi", handler("text"))
+	r.Callback("open", handler("callback"))
+	r.State(STATE_UPDATE_BIO, handler("state"))
+	r.UnknownCommand(handler("unknown"))
+	r.Fallback(handler("fallback"))
+
+	tests := []struct {
+		name    string
+		update  tgbotapi.Update
+		state   string
+		want    string
+		wantArg string
+	}{
+		{name: "buyruq", update: commandUpdate(42, "/start"), want: "command"},
+		{name: "noma'lum buyruq", update: commandUpdate(42, "/nope"), want: "unknown"},
+		{name: "buyruq holatdan ustun", update: commandUpdate(42, "/start"), state: STATE_UPDATE_BIO, want: "command"},
+		{name: "tugma matni holatdan ustun", update: messageUpdate(42, "🧪 Sinov tugmasi"), state: STATE_UPDATE_BIO, want: "text"},
+		{name: "holat", update: messageUpdate(42, "yangi bio"), state: STATE_UPDATE_BIO, want: "state"},
+		{name: "fallback", update: messageUpdate(42, "salom"), want: "fallback"},
+		{name: "callback prefiksi", update: callbackUpdate(42, "open:ab:cd"), want: "callback", wantArg: "ab:cd"},
+		{name: "noma'lum callback", update: callbackUpdate(42, "close:1"), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, _ := setupTestBot(t)
+			getUserState(42).State = tt.state
+			called, arg = "", ""
+
+			r.Handle(bot, tt.update)
+			if called != tt.want || arg != tt.wantArg {
+				t.Errorf("handler = %q (arg %q), want %q (arg %q)", called, arg, tt.want, tt.wantArg)
+			}
+		})
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	r := newRouter()
+	handled := false
+	r.Command("admin", func(ctx *Context) { handled = true }, adminOnly)
+	r.Command("start", func(ctx *Context) { handled = true })
+	r.State(STATE_ADD_ADMIN, func(ctx *Context) { handled = true }, adminOnly)
+	r.Fallback(func(ctx *Context) {})
+
+	tests := []struct {
+		name        string
+		username    string
+		state       string
+		text        string
+		wantHandled bool
+		wantReject  bool
+		wantState   string
+	}{
+		{name: "admin buyrug'i admin uchun", username: "boss", text: "/admin", wantHandled: true},
+		{name: "admin buyrug'i oddiy foydalanuvchiga", username: "guest", text: "/admin", wantReject: true},
+		{name: "oddiy buyruq", username: "guest", text: "/start", wantHandled: true},
+		{name: "admin jarayoni admin uchun davom etadi", username: "boss", state: STATE_ADD_ADMIN, text: "@new", wantHandled: true, wantState: STATE_ADD_ADMIN},
+		{name: "adminlikdan olinganning jarayoni tozalanadi", username: "former", state: STATE_ADD_ADMIN, text: "@new", wantState: STATE_NONE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, fake := setupTestBot(t)
+			state := getUserState(42)
+			if tt.state != "" {
+				state.State = tt.state
+			}
+			handled = false
+
+			var update tgbotapi.Update
+			if strings.HasPrefix(tt.text, "/") {
+				update = commandUpdate(42, tt.text)
+			} else {
+				update = messageUpdate(42, tt.text)
+			}
+			update.Message.From.UserName = tt.username
+			chain(r.Handle, requireAdmin(r))(bot, update)
+
+			if handled != tt.wantHandled {
+				t.Errorf("handled = %v, want %v", handled, tt.wantHandled)
+			}
+			rejected := false
+			for _, request := range fake.take() {
+				if strings.Contains(request.Form.Get("text"), "faqat adminlar uchun") {
+					rejected = true
+				}
+			}
+			if rejected != tt.wantReject {
+				t.Errorf("rad etildi = %v, want %v", rejected, tt.wantReject)
+			}
+			if tt.wantState != "" && state.State != tt.wantState {
+				t.Errorf("holat = %q, want %q", state.State, tt.wantState)
+			}
+		})
+	}
+}
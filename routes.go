@@ -0,0 +1,116 @@
+package main
+
+// Botning barcha handlerlarini ro'yxatdan o'tkazish.
+// Yangi buyruq yoki tugma uchun shu yerga yozuv qo'shish kifoya; kontent
+// bo'limlari esa kod yozmasdan admin paneldagi "🗂 Bo'limlar" orqali qo'shiladi.
+func newBotRouter() *router {
+	r := newRouter()
+
+	// Buyruqlar
+	r.Command("start", handleStartCommand)
+	r.Command("create", handleNewEntryButton, adminOnly)
+	r.Command("cancel", handleCancel)
+	r.UnknownCommand(handleUnknownCommand)
+
+	// Foydalanuvchi menyusi (bo'lim tugmalari omborda saqlanadi va fallback orqali ishlanadi)
+	r.Text("⬅️ Rollar", handleRolesButton)
+	r.Text("⬅️ Orqaga", handleBackButton)
+	r.Text(tagsButton, handleTagsButton)
+	r.Callback("browse_page", handleBrowsePageCallback)
+	r.Callback("browse_open", handleBrowseOpenCallback)
+	r.Callback("nav_section", handleNavSectionCallback)
+	r.Callback("nav_role", handleNavRoleCallback)
+	r.Callback("nav_tags", handleNavTagsCallback)
+	r.Callback("nav_tag", handleNavTagCallback)
+	r.Callback("nav_back", handleNavBackCallback)
+	r.Callback("entry_videos", handleEntryVideosCallback)
+	r.Callback("noop", handleNoopCallback)
+	r.Text(wizardCancelButton, handleCancel)
+
+	// Admin menyusi
+	r.Text("➕ Yangi yozuv", handleNewEntryButton, adminOnly)
+	r.Text(newEntryHereButton, handleNewEntryHereButton, adminOnly)
+	r.Callback("new_entry_here", handleNewEntryHereButton, adminOnly)
+	r.Text("🔧 Yozuvlarni boshqarish", handleManageEntriesButton, adminOnly)
+	r.Text("🗂 Bo'limlar", handleSectionsButton, adminOnly)
+	r.Text("🎮 Rollar", handleRolesAdminButton, adminOnly)
+	r.Text("📊 Statistika", handleStatisticsButton, adminOnly)
+	r.Text("👥 Adminlar", handleAdminsButton, adminOnly)
+
+	// Yozuvlarni boshqarish ("amal:bo'lim:nom" ko'rinishidagi callbacklar)
+	r.Callback("manage_section", handleManageSectionCallback, adminOnly)
+	r.Callback("manage_page", handleManagePageCallback, adminOnly)
+	r.Callback("manage_entry", handleManageEntryCallback, adminOnly)
+	r.Callback("delete_entry", handleDeleteEntryCallback, adminOnly)
+	r.Callback("confirm_delete", handleConfirmDeleteCallback, adminOnly)
+	r.Callback("cancel_delete", handleCancelDeleteCallback, adminOnly)
+	r.Callback("update_bio", handleUpdateBioCallback, adminOnly)
+	r.Callback("rename_entry", handleRenameEntryCallback, adminOnly)
+	r.Callback("add_video", handleAddVideoCallback, adminOnly)
+	r.Callback("manage_videos", handleManageVideosCallback, adminOnly)
+	r.Callback("video_preview", handleVideoPreviewCallback, adminOnly)
+	r.Callback("video_up", handleVideoUpCallback, adminOnly)
+	r.Callback("video_down", handleVideoDownCallback, adminOnly)
+	r.Callback("video_info", handleVideoInfoCallback, adminOnly)
+	r.Callback("video_thumb", handleVideoThumbnailCallback, adminOnly)
+	r.Callback("video_replace", handleVideoReplaceCallback, adminOnly)
+	r.Callback("video_delete", handleVideoDeleteCallback, adminOnly)
+	r.Callback("toggle_album", handleToggleAlbumCallback, adminOnly)
+	r.Callback("update_role", handleUpdateRoleCallback, adminOnly)
+	r.Callback("update_tags", handleUpdateTagsCallback, adminOnly)
+	r.Callback("toggle_pin", handleTogglePinCallback, adminOnly)
+	r.Callback("update_position", handleUpdatePositionCallback, adminOnly)
+
+	// Bo'limlarni boshqarish
+	r.Callback("add_section", handleAddSectionCallback, adminOnly)
+	r.Callback("rename_section", handleRenameSectionCallback, adminOnly)
+	r.Callback("section_emoji", handleSectionEmojiCallback, adminOnly)
+	r.Callback("move_section", handleMoveSectionCallback, adminOnly)
+	r.Callback("delete_section", handleDeleteSectionCallback, adminOnly)
+	r.Callback("confirm_delete_section", handleConfirmDeleteSectionCallback, adminOnly)
+
+	// Rollarni boshqarish
+	r.Callback("add_role", handleAddRoleCallback, adminOnly)
+	r.Callback("rename_role", handleRenameRoleCallback, adminOnly)
+	r.Callback("move_role", handleMoveRoleCallback, adminOnly)
+	r.Callback("toggle_role", handleToggleRoleCallback, adminOnly)
+	r.Callback("delete_role", handleDeleteRoleCallback, adminOnly)
+	r.Callback("confirm_delete_role", handleConfirmDeleteRoleCallback, adminOnly)
+
+	// Adminlar va hisobotlar
+	r.Callback("download_logs", handleDownloadLogsCallback, adminOnly)
+	r.Callback("add_admin", handleAddAdminCallback, adminOnly)
+	r.Callback("remove_admin", handleRemoveAdminCallback, adminOnly)
+	r.Callback("confirm_remove_admin", handleConfirmRemoveAdminCallback, adminOnly)
+
+	// Yangi yozuv va bo'lim yaratish ustalari
+	r.Wizard(createEntryWizard, adminOnly)
+	r.Wizard(createSectionWizard, adminOnly)
+	r.Wizard(videoInfoWizard, adminOnly)
+
+	// Yozuvni tahrirlash
+	r.State(STATE_UPDATE_BIO, handleUpdateBioInput, adminOnly)
+	r.State(STATE_RENAME_ENTRY, handleRenameEntryInput, adminOnly)
+	r.State(STATE_ADD_VIDEO, handleAddVideoInput, adminOnly)
+	r.State(STATE_REPLACE_VIDEO, handleReplaceVideoInput, adminOnly)
+	r.State(STATE_VIDEO_THUMBNAIL, handleVideoThumbnailInput, adminOnly)
+	r.State(STATE_UPDATE_ROLE, handleUpdateRoleInput, adminOnly)
+	r.State(STATE_UPDATE_TAGS, handleUpdateTagsInput, adminOnly)
+	r.State(STATE_UPDATE_POSITION, handleUpdatePositionInput, adminOnly)
+
+	// Bo'limni tahrirlash
+	r.State(STATE_RENAME_SECTION, handleRenameSectionInput, adminOnly)
+	r.State(STATE_SECTION_EMOJI, handleSectionEmojiInput, adminOnly)
+
+	// Rollar
+	r.State(STATE_ADD_ROLE, handleAddRoleInput, adminOnly)
+	r.State(STATE_RENAME_ROLE, handleRenameRoleInput, adminOnly)
+
+	// Adminlar
+	r.State(STATE_ADD_ADMIN, handleAddAdminInput, adminOnly)
+
+	// Bo'lim, rol yoki yozuv tanlash
+	r.Fallback(handleSelection)
+
+	return r
+}
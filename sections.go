@@ -0,0 +1,1601 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Section - kontent bo'limi (masalan, "Tutorials" yoki "Geroylar tarixi").
+// Bo'limlar ma'lumotlar omborida saqlanadi va admin paneldan qo'shiladi,
+// shuning uchun yangi bo'lim uchun alohida kod yozish shart emas.
+type Section struct {
+	ID       string `json:"id"` // callback va holatlarda ishlatiladigan qisqa kalit
+	Name     string `json:"name"`
+	Emoji    string `json:"emoji"`
+	Position int    `json:"position"` // menyudagi tartib raqami
+}
+
+// Entry - bo'lim yozuvi: tavsif, rollar, teglar va private kanaldagi videolar.
+// Bitta yozuv bir nechta rolda ko'rinishi mumkin (masalan, Fighter va Assassin).
+type Entry struct {
+	ID        string    `json:"id"` // o'zgarmas qisqa kalit (callback, havola va jurnallar uchun)
+	Bio       string    `json:"bio"`
+	Roles     []string  `json:"roles"`
+	Tags      []string  `json:"tags,omitempty"`
+	Videos    []Video   `json:"videos"`
+	Position  int       `json:"position,omitempty"` // admin bergan tartib raqami (0 - alifbo bo'yicha)
+	Pinned    bool      `json:"pinned,omitempty"`   // ro'yxat boshida ko'rsatiladi
+	Separate  bool      `json:"separate,omitempty"` // videolar albom emas, alohida xabarlarda yuboriladi
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"` // bio yoki videolar oxirgi marta o'zgargan vaqt
+}
+
+// Yozuv berilgan rolga tegishlimi
+func (e Entry) HasRole(role string) bool {
+	for _, r := range e.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Yozuvda berilgan teg bormi
+func (e Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Bo'limning menyu tugmasi matni
+func (s Section) Button() string {
+	if s.Emoji == "" {
+		return s.Name
+	}
+	return s.Emoji + " " + s.Name
+}
+
+// Yangi o'rnatishda yaratiladigan bo'limlar
+func defaultSections() []Section {
+	return []Section{
+		{ID: "tutorials", Name: "Tutorials", Emoji: "📚", Position: 1},
+		{ID: "stories", Name: "Geroylar tarixi", Emoji: "📖", Position: 2},
+	}
+}
+
+// Bo'limlarni menyudagi tartibda saralash
+func sortSections(sections []Section) {
+	sort.Slice(sections, func(i, j int) bool {
+		if sections[i].Position != sections[j].Position {
+			return sections[i].Position < sections[j].Position
+		}
+		return sections[i].ID < sections[j].ID
+	})
+}
+
+// Menyu tugmasi bo'yicha bo'limni topish. Eski klaviaturalardagi emojisiz
+// nom ham qabul qilinadi.
+func sectionByButton(text string) (Section, bool) {
+	sections, err := store.Sections()
+	if err != nil {
+		log.Printf("Bo'limlarni o'qishda xatolik: %v", err)
+		return Section{}, false
+	}
+	for _, section := range sections {
+		if text == section.Button() || text == section.Name {
+			return section, true
+		}
+	}
+	return Section{}, false
+}
+
+// Bo'lim ID si bo'yicha tugma matni (bo'lim topilmasa ID ning o'zi)
+func sectionLabel(id string) string {
+	if section, exists, err := store.Section(id); err == nil && exists {
+		return section.Button()
+	}
+	return id
+}
+
+// Bo'lim nomidan callbacklarga mos qisqa ID yasash
+func sectionSlug(name string, taken func(id string) bool) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			underscore = false
+		case b.Len() > 0 && !underscore:
+			b.WriteByte('_')
+			underscore = true
+		}
+		if b.Len() >= 24 {
+			break
+		}
+	}
+	base := strings.Trim(b.String(), "_")
+	if base == "" {
+		base = "section"
+	}
+
+	id := base
+	for i := 2; taken(id); i++ {
+		id = base + "_" + strconv.Itoa(i)
+	}
+	return id
+}
+
+// Bo'lim tugmalari qatorlari (har qatorda ikkitadan)
+func sectionButtonRows() [][]tgbotapi.KeyboardButton {
+	sections, err := store.Sections()
+	if err != nil {
+		log.Printf("Bo'limlarni o'qishda xatolik: %v", err)
+		return nil
+	}
+	var rows [][]tgbotapi.KeyboardButton
+	for i := 0; i < len(sections); i += 2 {
+		row := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(sections[i].Button()))
+		if i+1 < len(sections) {
+			row = append(row, tgbotapi.NewKeyboardButton(sections[i+1].Button()))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Rol tanlangan bo'limda yozuv qo'shish tugmasi (bo'lim va rol avtomatik olinadi)
+const newEntryHereButton = "➕ Shu yerga yozuv qo'shish"
+
+// Bo'limni ochish: rollar ro'yxatini ko'rsatish
+func showSection(ctx *Context, section Section) {
+	entries, err := store.Entries(section.ID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	// Bo'sh bo'limda faqat admin rol tanlab, yozuv qo'shishi mumkin
+	admin := isAdmin(ctx.User.UserName)
+	if len(entries) == 0 && !admin {
+		text := fmt.Sprintf("Hozircha %s bo'limida yozuvlar mavjud emas.", section.Button())
+		if inlineNavigation() {
+			resetUserState(ctx.User.ID)
+			sendOrEdit(ctx, editInPlace(ctx), text, tgbotapi.NewInlineKeyboardMarkup(inlineBackRow("")))
+			return
+		}
+		sendMessage(ctx.Bot, ctx.ChatID, text)
+		sendMainMenu(ctx.Bot, ctx.ChatID)
+		return
+	}
+
+	ctx.State.TempData["section"] = section.ID
+	delete(ctx.State.TempData, "selectedRole")
+	delete(ctx.State.TempData, "path")
+	delete(ctx.State.TempData, "tags")
+	delete(ctx.State.TempData, "tag")
+
+	message := fmt.Sprintf("%s: qaysi roldagi yozuvlarni ko'rmoqchisiz?", section.Button())
+	if len(entries) == 0 {
+		message = fmt.Sprintf("Hozircha %s bo'limida yozuvlar mavjud emas. Rol tanlab, yangi yozuv qo'shishingiz mumkin.", section.Button())
+	}
+
+	// Yashirin rollar faqat adminlarga ko'rinadi
+	roles := roleNames(admin)
+	hasTags := len(sectionTags(entries)) > 0
+
+	if inlineNavigation() {
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for i := 0; i < len(roles); i += 2 {
+			row := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(roles[i], sectionCallback("nav_role:", section.ID, roles[i])))
+			if i+1 < len(roles) {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(roles[i+1], sectionCallback("nav_role:", section.ID, roles[i+1])))
+			}
+			rows = append(rows, row)
+		}
+		back := inlineBackRow("")
+		if hasTags {
+			back = append(tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(tagsButton, "nav_tags:"+section.ID)), back...)
+		}
+		rows = append(rows, back)
+		sendOrEdit(ctx, editInPlace(ctx), message, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows})
+		return
+	}
+
+	// Teglar bo'lsa, ular bo'yicha ko'rish tugmasi qo'shiladi
+	back := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("⬅️ Orqaga"))
+	if hasTags {
+		back = append(tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(tagsButton)), back...)
+	}
+
+	msg := tgbotapi.NewMessage(ctx.ChatID, message)
+	msg.ReplyMarkup = roleKeyboard(roles, back)
+	outbox.Send(msg)
+}
+
+// Nom bo'yicha yozuvni qidirish: avval joriy bo'limda, keyin qolganlarida
+func findEntry(currentSectionID, title string) (Section, bool, error) {
+	sections, err := store.Sections()
+	if err != nil {
+		return Section{}, false, err
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].ID == currentSectionID && sections[j].ID != currentSectionID
+	})
+	for _, section := range sections {
+		_, exists, err := store.Entry(section.ID, title)
+		if err != nil {
+			return Section{}, false, err
+		}
+		if exists {
+			return section, true, nil
+		}
+	}
+	return Section{}, false, nil
+}
+
+// Yozuv tarkibini ko'rsatish
+func showEntry(ctx *Context, section Section, title string) {
+	entry, exists, err := store.Entry(section.ID, title)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !exists {
+		sendMessage(ctx.Bot, ctx.ChatID, "Bunday yozuv topilmadi.")
+		sendMainMenu(ctx.Bot, ctx.ChatID)
+		return
+	}
+
+	ctx.State.State = STATE_ENTRY_SELECTED
+	ctx.State.TempData["section"] = section.ID
+	ctx.State.TempData["selectedTitle"] = title
+
+	caption := entryCaption(section, title, entry)
+
+	// Inline rejimda yozuv ma'lumoti ro'yxat xabarining o'rnida ko'rsatiladi,
+	// videolar esa so'ralganda alohida yuboriladi
+	if inlineNavigation() {
+		text := caption + "\n\nBu yozuvda hali videolar mavjud emas."
+		var rows [][]tgbotapi.InlineKeyboardButton
+		if len(entry.Videos) > 0 {
+			text = fmt.Sprintf("%s\n\n🎬 Videolar: %d ta", caption, len(entry.Videos))
+			if videosTitled(entry.Videos) {
+				for i, video := range entry.Videos {
+					text += fmt.Sprintf("\n%d. %s", i+1, video.Label())
+				}
+			}
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("▶️ Videolarni ko'rish", "entry_videos:"+entry.ID),
+			))
+		}
+		rows = append(rows, inlineBackRow(entryBackTarget(ctx.State, section, title, entry)))
+		sendOrEdit(ctx, editInPlace(ctx), text, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows})
+		return
+	}
+
+	sendEntryVideos(ctx, section.ID, title, entry, caption)
+
+	// Agar hech qanday video bo'lmasa, faqat ma'lumotni yuboramiz
+	if len(entry.Videos) == 0 {
+		sendMessage(ctx.Bot, ctx.ChatID, caption+"\n\nBu yozuvda hali videolar mavjud emas.")
+	}
+
+	backKeyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("⬅️ Orqaga")),
+	)
+	backKeyboard.ResizeKeyboard = true
+
+	msg := tgbotapi.NewMessage(ctx.ChatID, "Orqaga qaytish uchun tugmani bosing.")
+	msg.ReplyMarkup = backKeyboard
+	outbox.Send(msg)
+}
+
+// Yozuv sarlavhasi, rollari, teglari va tavsifi
+func entryCaption(section Section, title string, entry Entry) string {
+	heading := title
+	if section.Emoji != "" {
+		heading = section.Emoji + " " + title
+	}
+	roleInfo := ""
+	if len(entry.Roles) > 0 {
+		roleInfo = fmt.Sprintf("\n🎮 Rol: %s", strings.Join(entry.Roles, ", "))
+	}
+	if len(entry.Tags) > 0 {
+		roleInfo += "\n🏷 " + formatTags(entry.Tags)
+	}
+	return fmt.Sprintf("%s%s\n\n%s", heading, roleInfo, entry.Bio)
+}
+
+// Yozuv videolarini yuborish. Fayl ID si ma'lum bo'lgan ketma-ket videolar
+// (2-10 ta) albom bo'lib, qolganlari, yozuvda "alohida" sozlamasi bo'lsa yoki
+// albom yuborilmasa har biri o'z nomi va tavsifi bilan alohida yuboriladi.
+// Birinchi xabar yozuv ma'lumotini ham oladi. Alohida yuborishda aniqlangan
+// fayl ID lari yozuvga saqlanadi, keyingi safar ular ham albomga kiradi.
+func sendEntryVideos(ctx *Context, sectionID, title string, entry Entry, caption string) {
+	learned := make(map[string]string)
+	total := len(entry.Videos)
+	for start := 0; start < total; {
+		end := start + 1
+		if !entry.Separate && entry.Videos[start].FileID != "" {
+			for end < total && end-start < mediaGroupLimit && entry.Videos[end].FileID != "" {
+				end++
+			}
+		}
+		heading := ""
+		if start == 0 {
+			heading = caption
+		}
+		if end-start > 1 && sendVideoAlbum(ctx.ChatID, entry.Videos, start, end, heading) {
+			start = end
+			continue
+		}
+
+		for i := start; i < end; i++ {
+			video := entry.Videos[i]
+			text := videoCaption(video, i, total)
+			if i == 0 {
+				text = strings.TrimSpace(strings.TrimSpace(caption) + "\n\n" + text)
+			}
+
+			fileID, ok := deliverVideo(ctx.ChatID, video, fitCaption(text))
+			if !ok {
+				sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("Videoni yuborib bo'lmadi (%d-video). Iltimos, keyinroq qayta urinib ko'ring.", i+1))
+				continue
+			}
+			// Ishlamay qolgan fayl ID si yangisi (yoki bo'sh qiymat) bilan almashtiriladi
+			if fileID != video.FileID {
+				learned[video.ID] = fileID
+			}
+		}
+		start = end
+	}
+	rememberFileIDs(sectionID, title, learned)
+}
+
+// "⬅️ Rollar" tugmasi
+func handleRolesButton(ctx *Context) {
+	logUserAction(ctx.User, "Rollar menyusiga qaytdi", "")
+	section, exists, err := store.Section(ctx.State.TempData["section"])
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !exists {
+		sendMenu(ctx)
+		return
+	}
+	showSection(ctx, section)
+}
+
+// "⬅️ Orqaga" tugmasi
+func handleBackButton(ctx *Context) {
+	logUserAction(ctx.User, "Orqaga qaytdi", "")
+
+	selected := ctx.State.State == STATE_ENTRY_SELECTED
+	sectionID := ctx.State.TempData["section"]
+	role := ctx.State.TempData["selectedRole"]
+	path := currentPath(ctx.State)
+	tag := ctx.State.TempData["tag"]
+	tagList := ctx.State.TempData["tags"] != ""
+	title := ctx.State.TempData["selectedTitle"]
+	resetUserState(ctx.User.ID)
+
+	section, exists, err := store.Section(sectionID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !exists {
+		showTopMenu(ctx)
+		return
+	}
+
+	switch {
+	case selected && tag != "":
+		// Teg orqali ochilgan yozuvdan teg ro'yxatiga qaytish
+		showTag(ctx, section, tag)
+	case selected:
+		// Yozuvdan uning kategoriyasiga qaytish (rol tanlanmagan bo'lsa, birinchi roli)
+		entry, found, _ := store.Entry(sectionID, title)
+		if found && len(entry.Roles) > 0 {
+			if !entry.HasRole(role) {
+				role = entry.Roles[0]
+			}
+			entryPath, _ := splitEntryPath(title)
+			showSectionRole(ctx, section, role, entryPath)
+		} else {
+			showSection(ctx, section)
+		}
+	case tag != "":
+		showSectionTags(ctx, section)
+	case len(path) > 0:
+		// Bir daraja yuqoriga
+		showSectionRole(ctx, section, role, path[:len(path)-1])
+	case role != "" || tagList:
+		showSection(ctx, section)
+	default:
+		showTopMenu(ctx)
+	}
+}
+
+// Boshqa xabarlar: bo'lim tugmasi, rol yoki yozuv nomi bo'lishi mumkin
+func handleSelection(ctx *Context) {
+	// Bo'lim tanlash
+	if section, exists := sectionByButton(ctx.Text); exists {
+		logUserAction(ctx.User, "Bo'limga kirdi", section.Name)
+		showSection(ctx, section)
+		return
+	}
+
+	// Joriy kategoriyadagi ichki kategoriya yoki yozuvni tanlash
+	if selectInCategory(ctx) {
+		return
+	}
+
+	// Teg tanlash
+	if selectTag(ctx) {
+		return
+	}
+
+	// Rol tanlash
+	if isRole(ctx.Text, isAdmin(ctx.User.UserName)) {
+		section, exists, err := store.Section(ctx.State.TempData["section"])
+		if err != nil {
+			reportStoreError(ctx.Bot, ctx.ChatID, err)
+			return
+		}
+		if !exists {
+			sendMessage(ctx.Bot, ctx.ChatID, "Avval bo'limni tanlang.")
+			sendMenu(ctx)
+			return
+		}
+		logUserAction(ctx.User, fmt.Sprintf("%s: '%s' rolini ko'rdi", section.Name, ctx.Text), "")
+		showSectionRole(ctx, section, ctx.Text, nil)
+		return
+	}
+
+	// Yozuv tanlash
+	section, exists, err := findEntry(ctx.State.TempData["section"], ctx.Text)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if exists {
+		logUserAction(ctx.User, "Yozuv ko'rildi", entryLogRef(section.ID, ctx.Text))
+		showEntry(ctx, section, ctx.Text)
+	}
+}
+
+// Kategoriya ajratuvchisi atrofidagi bo'shliqlar ("Alucard   /  Laning")
+var categorySeparatorPattern = regexp.MustCompile(`\s+/\s+`)
+
+// Nomni tekshirish. "Alucard / Laning" ko'rinishidagi nom yozuvni
+// kategoriya ichiga joylaydi; qismlar orasidagi bo'shliqlar bir xillashtiriladi.
+func validateTitle(value string) (string, error) {
+	if strings.HasPrefix(value, "/") {
+		return "", errors.New("Nom / belgisi bilan boshlanmasligi kerak.")
+	}
+	value = categorySeparatorPattern.ReplaceAllString(value, categorySeparator)
+	path, name := splitEntryPath(value)
+	parts := append(path, name)
+	for _, part := range parts {
+		if strings.HasPrefix(part, strings.TrimSpace(categoryButtonPrefix)) {
+			return "", fmt.Errorf("Nom %s belgisi bilan boshlanmasligi kerak.", strings.TrimSpace(categoryButtonPrefix))
+		}
+	}
+	value = joinEntryPath(parts...)
+	if utf8.RuneCountInString(value) > 100 {
+		return "", errors.New("Nom juda uzun (ko'pi bilan 100 ta belgi).")
+	}
+	return value, nil
+}
+
+// Video ID ni tekshirish (private kanaldagi xabar raqami)
+func validateVideoID(value string) (string, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return "", errors.New("Video ID musbat butun son bo'lishi kerak (private kanaldagi xabar raqami).")
+	}
+	return strconv.Itoa(id), nil
+}
+
+// Bo'lim tanlovlari (tugma matnlari)
+func sectionChoices(data map[string]string) []string {
+	sections, err := store.Sections()
+	if err != nil {
+		log.Printf("Bo'limlarni o'qishda xatolik: %v", err)
+		return nil
+	}
+	choices := make([]string, 0, len(sections))
+	for _, section := range sections {
+		choices = append(choices, section.Button())
+	}
+	return choices
+}
+
+// Yangi yozuv yaratish ustasi
+var createEntryWizard = &wizard{
+	name:    "create_entry",
+	heading: "📝 Yangi yozuv:",
+	steps: []wizardStep{
+		{
+			key:     "entry_section",
+			label:   "Bo'lim",
+			choices: sectionChoices,
+			prompt:  func(data map[string]string) string { return "Yozuv qaysi bo'limga qo'shilsin?" },
+			validate: func(value string) (string, error) {
+				section, exists := sectionByButton(value)
+				if !exists {
+					return "", errors.New("Bunday bo'lim topilmadi.")
+				}
+				return section.ID, nil
+			},
+			format: sectionLabel,
+			// Bo'lim ichidan yaratilayotgan bo'lsa, o'sha bo'lim olinadi
+			auto: func(data map[string]string) (string, bool) {
+				_, exists, _ := store.Section(data["section"])
+				return data["section"], exists
+			},
+		},
+		{
+			key:   "title",
+			label: "Nomi",
+			prompt: func(data map[string]string) string {
+				if path := entryPathPrefix(data); path != "" {
+					return fmt.Sprintf("'%s' kategoriyasi uchun yangi yozuv nomini kiriting (ichki kategoriya uchun nomlarni \" / \" bilan ajrating):", path)
+				}
+				return fmt.Sprintf("%s bo'limi uchun yangi yozuv nomini kiriting (kategoriya uchun \" / \" bilan ajrating, masalan: Alucard / Laning):", sectionLabel(data["entry_section"]))
+			},
+			validate: validateTitle,
+		},
+		{
+			key:      "bio",
+			label:    "Bio",
+			optional: true,
+			prompt: func(data map[string]string) string {
+				return fmt.Sprintf("'%s' uchun qisqacha tavsif (bio) kiriting:", data["title"])
+			},
+		},
+		{
+			key:     "role",
+			label:   "Rol",
+			choices: func(data map[string]string) []string { return roleNames(true) },
+			prompt: func(data map[string]string) string {
+				return fmt.Sprintf("'%s' uchun rolni tanlang:", data["title"])
+			},
+			// Rol tanlagandan keyin yaratilayotgan bo'lsa, o'sha rol olinadi
+			auto: func(data map[string]string) (string, bool) {
+				role := data["selectedRole"]
+				return role, role != "" && data["section"] == data["entry_section"]
+			},
+		},
+		{
+			key:      "tags",
+			label:    "Teglar",
+			optional: true,
+			prompt: func(data map[string]string) string {
+				return fmt.Sprintf("'%s' uchun teglarni vergul bilan ajratib kiriting (masalan: beginner, jungle):", data["title"])
+			},
+			validate: validateTags,
+		},
+		{
+			key:      "video",
+			label:    "Video ID",
+			multiple: true,
+			prompt: func(data map[string]string) string {
+				return "Endi videolarni qo'shing (bir nechta bo'lishi mumkin). " + videoInputHint
+			},
+			message: wizardVideo,
+			format:  func(value string) string { return strings.Join(splitList(value), ", ") },
+		},
+	},
+	finish: finishCreateEntry,
+}
+
+// Kategoriya ichidan yaratilayotgan yozuv uchun kategoriya yo'li
+// (bo'lim yoki rol boshqasi tanlangan bo'lsa bo'sh)
+func entryPathPrefix(data map[string]string) string {
+	if data["path"] == "" || data["section"] != data["entry_section"] {
+		return ""
+	}
+	if data["role"] != "" && data["role"] != data["selectedRole"] {
+		return ""
+	}
+	return data["path"]
+}
+
+// Yangi yozuvni saqlash (mavjud bo'lsa, unga video qo'shiladi)
+func finishCreateEntry(ctx *Context, data map[string]string) error {
+	sectionID, title := data["entry_section"], data["title"]
+	if path := entryPathPrefix(data); path != "" {
+		title = joinEntryPath(path, title)
+	}
+	tags, _ := parseTags(data["tags"])
+	videos := wizardVideos(data)
+	duplicates := 0
+	err := store.UpdateEntry(sectionID, title, func(entry *Entry, exists bool) error {
+		if !exists {
+			*entry = Entry{
+				Bio:       data["bio"],
+				Roles:     []string{data["role"]},
+				Tags:      tags,
+				Videos:    videos,
+				CreatedAt: time.Now(),
+			}
+			return nil
+		}
+		// Mavjud yozuvga videolar, yangi rol va teglar qo'shiladi
+		for _, video := range videos {
+			if videoIndex(entry.Videos, video.ID) >= 0 {
+				duplicates++
+				continue
+			}
+			entry.Videos = append(entry.Videos, video)
+		}
+		entry.UpdatedAt = time.Now()
+		if data["bio"] != "" {
+			entry.Bio = data["bio"]
+		}
+		if !entry.HasRole(data["role"]) {
+			entry.Roles = append(entry.Roles, data["role"])
+		}
+		for _, tag := range tags {
+			if !entry.HasTag(tag) {
+				entry.Tags = append(entry.Tags, tag)
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errNotFound) {
+		// Usta davomida bo'lim o'chirilgan
+		sendMessage(ctx.Bot, ctx.ChatID, "Bo'lim topilmadi. \""+wizardBackButton+"\" orqali boshqa bo'lim tanlang.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	logUserAction(ctx.User, "Admin: Yangi yozuv yaratildi", entryLogRef(sectionID, title))
+	if duplicates > 0 {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("%s: '%s' saqlandi. %d ta video yozuvda allaqachon bor edi, qayta qo'shilmadi.", sectionLabel(sectionID), title, duplicates))
+	} else {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("%s: '%s' muvaffaqiyatli saqlandi va %d ta video qo'shildi!", sectionLabel(sectionID), title, len(videos)))
+	}
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	return nil
+}
+
+// /create buyrug'i va "➕ Yangi yozuv" tugmasi
+func handleNewEntryButton(ctx *Context) {
+	logUserAction(ctx.User, "Admin: Yangi yozuv yaratish boshlandi", "")
+	delete(ctx.State.TempData, "section")
+	delete(ctx.State.TempData, "selectedRole")
+	delete(ctx.State.TempData, "path")
+	createEntryWizard.start(ctx)
+}
+
+// "➕ Shu yerga yozuv qo'shish" tugmasi: bo'lim va rol oldindan tanlangan
+func handleNewEntryHereButton(ctx *Context) {
+	logUserAction(ctx.User, "Admin: Yangi yozuv yaratish boshlandi", ctx.State.TempData["section"])
+	createEntryWizard.start(ctx)
+}
+
+// "🔧 Yozuvlarni boshqarish" tugmasi
+func handleManageEntriesButton(ctx *Context) {
+	logUserAction(ctx.User, "Admin: Yozuvlarni boshqarish", "")
+	sections, err := store.Sections()
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	switch len(sections) {
+	case 0:
+		sendMessage(ctx.Bot, ctx.ChatID, "Hozircha bo'limlar mavjud emas. \"🗂 Bo'limlar\" orqali yangi bo'lim qo'shing.")
+	case 1:
+		delete(ctx.State.TempData, "adminTag")
+		showSectionForAdmin(ctx, sections[0], 0)
+	default:
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for _, section := range sections {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(section.Button(), "manage_section:"+section.ID),
+			))
+		}
+		msg := tgbotapi.NewMessage(ctx.ChatID, "Qaysi bo'lim yozuvlarini boshqarmoqchisiz?")
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+		outbox.Send(msg)
+	}
+}
+
+// Tanlangan bo'lim yozuvlarini boshqarish
+func handleManageSectionCallback(ctx *Context) {
+	section, ok := callbackSection(ctx)
+	if !ok {
+		return
+	}
+	// "manage_section:bo'lim:teg" - teg bo'yicha filtrlangan ro'yxat
+	_, tag, _ := strings.Cut(ctx.Arg, ":")
+	ctx.State.TempData["adminTag"] = tag
+	showSectionForAdmin(ctx, section, 0)
+}
+
+// Admin ro'yxati sahifasini almashtirish ("manage_page:bo'lim:sahifa")
+func handleManagePageCallback(ctx *Context) {
+	section, ok := callbackSection(ctx)
+	if !ok {
+		return
+	}
+	_, page, _ := strings.Cut(ctx.Arg, ":")
+	showSectionForAdmin(ctx, section, callbackNumber(page))
+}
+
+// Admin uchun yozuv qatori: nom, rollar va teglar
+func entrySummary(title string, entry Entry) string {
+	line := entryLabel(title, entry)
+	if entry.Position > 0 {
+		line += fmt.Sprintf(" | №%d", entry.Position)
+	}
+	if len(entry.Roles) > 0 {
+		line += fmt.Sprintf(" | Rol: %s", strings.Join(entry.Roles, ", "))
+	}
+	if len(entry.Tags) > 0 {
+		line += " | " + formatTags(entry.Tags)
+	}
+	return line
+}
+
+// Admin uchun bo'lim yozuvlarini bitta xabarda sahifalab ko'rsatish.
+// Teg filtri TempData["adminTag"] da saqlanadi; yozuv tugmalari yozuv ID sini yuboradi.
+func showSectionForAdmin(ctx *Context, section Section, page int) {
+	entries, err := store.Entries(section.ID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	if len(entries) == 0 {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("Hozircha %s bo'limida yozuvlar mavjud emas.", section.Button()))
+		sendAdminMenu(ctx.Bot, ctx.ChatID)
+		return
+	}
+
+	tag := ctx.State.TempData["adminTag"]
+	titles := sortedTitles(entries)
+	var visible []string
+	for _, title := range titles {
+		if tag == "" || entries[title].HasTag(tag) {
+			visible = append(visible, title)
+		}
+	}
+
+	text := fmt.Sprintf("%s bo'limidagi yozuvlar (%d ta). Boshqarish uchun yozuvni tanlang:", section.Button(), len(visible))
+	if tag != "" {
+		text = fmt.Sprintf("%s bo'limidagi %s%s tegli yozuvlar (%d ta):", section.Button(), tagButtonPrefix, tag, len(visible))
+	}
+
+	start, end, page, pages := pageBounds(len(visible), page, adminPageSize)
+	var lines []string
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for n := start; n < end; n++ {
+		title := visible[n]
+		lines = append(lines, fmt.Sprintf("%d. %s", n+1, entrySummary(title, entries[title])))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d. %s", n+1, title), "manage_entry:"+entries[title].ID),
+		))
+	}
+	if len(lines) > 0 {
+		text += "\n\n" + strings.Join(lines, "\n")
+	}
+	if pager := pagerRow("manage_page:"+section.ID+":", page, pages); pager != nil {
+		rows = append(rows, pager)
+	}
+
+	// Teglar bo'yicha filtr tugmalari
+	if tags := sectionTags(entries); len(tags) > 0 {
+		row := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Hammasi", "manage_section:"+section.ID))
+		for _, t := range tags {
+			if len(row) == 3 {
+				rows = append(rows, row)
+				row = nil
+			}
+			label := tagButtonPrefix + t
+			if t == tag {
+				label = "✅ " + label
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, "manage_section:"+section.ID+":"+t))
+		}
+		rows = append(rows, row)
+	}
+
+	sendOrEdit(ctx, true, text, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows})
+}
+
+// Admin ro'yxatidan yozuvni ochish: xabar joyida yozuv amallariga almashtiriladi
+func handleManageEntryCallback(ctx *Context) {
+	if section, title, ok := callbackEntry(ctx); ok {
+		showManagedEntry(ctx, section, title)
+	}
+}
+
+// Admin uchun yozuv ma'lumoti va amallari (callback kelgan xabar joyida)
+func showManagedEntry(ctx *Context, section Section, title string) {
+	entries, err := store.Entries(section.ID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	entry, exists := entries[title]
+	if !exists {
+		showSectionForAdmin(ctx, section, 0)
+		return
+	}
+
+	// Ro'yxatga qaytganda shu yozuv turgan sahifa ochiladi
+	tag := ctx.State.TempData["adminTag"]
+	position := 0
+	for _, other := range sortedTitles(entries) {
+		if other == title {
+			break
+		}
+		if tag == "" || entries[other].HasTag(tag) {
+			position++
+		}
+	}
+
+	text := fmt.Sprintf("%s %s\n\n🎬 Videolar: %d ta", section.Emoji, entrySummary(title, entry), len(entry.Videos))
+	if entry.Bio != "" {
+		text += "\n\n" + entry.Bio
+	}
+	if link := entryLink(ctx.Bot, entry.ID); link != "" {
+		text += "\n\n🔗 " + link
+	}
+
+	pinLabel := "📌 Qadash"
+	if entry.Pinned {
+		pinLabel = "📍 Qadashni bekor qilish"
+	}
+	ref := entry.ID
+	markup := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Bio o'zgartirish", "update_bio:"+ref),
+			tgbotapi.NewInlineKeyboardButtonData("🎮 Rol o'zgartirish", "update_role:"+ref),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🎬 Videolar (%d)", len(entry.Videos)), "manage_videos:"+ref),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🏷 Teglar", "update_tags:"+ref),
+			tgbotapi.NewInlineKeyboardButtonData(pinLabel, "toggle_pin:"+ref),
+			tgbotapi.NewInlineKeyboardButtonData("🔢 Tartib", "update_position:"+ref),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📝 Nomini o'zgartirish", "rename_entry:"+ref),
+			tgbotapi.NewInlineKeyboardButtonData("❌ O'chirish", "delete_entry:"+ref),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⬅️ Ro'yxatga qaytish", fmt.Sprintf("manage_page:%s:%d", section.ID, position/adminPageSize)),
+		),
+	)
+	sendOrEdit(ctx, true, text, markup)
+}
+
+// Callbackdagi bo'lim ID si bo'yicha bo'limni olish
+func callbackSection(ctx *Context) (Section, bool) {
+	sectionID, _, _ := strings.Cut(ctx.Arg, ":")
+	if sectionID == "" {
+		return Section{}, false
+	}
+	section, exists, err := store.Section(sectionID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return Section{}, false
+	}
+	if !exists {
+		sendMessage(ctx.Bot, ctx.ChatID, "Bo'lim topilmadi.")
+		return Section{}, false
+	}
+	return section, true
+}
+
+// Callback argumentidagi yozuv ID si bo'yicha bo'lim va yozuv nomini olish
+func callbackEntry(ctx *Context) (Section, string, bool) {
+	if ctx.Arg == "" {
+		return Section{}, "", false
+	}
+	sectionID, title, found, err := store.FindEntry(ctx.Arg)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return Section{}, "", false
+	}
+	if !found {
+		sendMessage(ctx.Bot, ctx.ChatID, "Yozuv topilmadi.")
+		return Section{}, "", false
+	}
+	section, exists, err := store.Section(sectionID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return Section{}, "", false
+	}
+	if !exists {
+		sendMessage(ctx.Bot, ctx.ChatID, "Bo'lim topilmadi.")
+		return Section{}, "", false
+	}
+	return section, title, true
+}
+
+// Yozuvni to'g'ridan-to'g'ri ochuvchi havola: https://t.me/<bot>?start=<ID>
+func entryLink(bot *tgbotapi.BotAPI, id string) string {
+	if bot == nil || bot.Self.UserName == "" || id == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/%s?start=%s", bot.Self.UserName, id)
+}
+
+// Jurnal uchun yozuv havolasi: "tutorials: Alucard [3f9a1c07]".
+// ID nom o'zgarganda ham saqlanadi, shuning uchun jurnaldan yozuvni topish oson.
+func entryLogRef(sectionID, title string) string {
+	ref := fmt.Sprintf("%s: %s", sectionID, title)
+	if entry, exists, err := store.Entry(sectionID, title); err == nil && exists && entry.ID != "" {
+		ref += " [" + entry.ID + "]"
+	}
+	return ref
+}
+
+// Jurnal yozuvidagi "[id]" qismi (eski yozuvlarda bo'lmasligi mumkin)
+var entryRefPattern = regexp.MustCompile(`\[([0-9a-f]+)\]$`)
+
+// Jurnal tafsilotlaridan yozuv ID sini ajratish ("" - ID yo'q)
+func entryRefID(details string) string {
+	if match := entryRefPattern.FindStringSubmatch(details); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// Yozuvni o'chirishni tasdiqlash so'rovi
+func handleDeleteEntryCallback(ctx *Context) {
+	section, title, ok := callbackEntry(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.State.State = STATE_CONFIRM_DELETE
+	ctx.State.TempData["deleteTitle"] = title
+
+	// Tasdiqlash so'rovi
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Ha, o'chirish", "confirm_delete:"+ctx.Arg),
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Bekor qilish", "cancel_delete"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("%s: '%s' yozuvini o'chirishni tasdiqlaysizmi?", section.Button(), title))
+	msg.ReplyMarkup = keyboard
+	outbox.Send(msg)
+}
+
+// Yozuvni o'chirish
+func handleConfirmDeleteCallback(ctx *Context) {
+	section, title, ok := callbackEntry(ctx)
+	if !ok {
+		return
+	}
+
+	// ID jurnalga yozilishi uchun havola o'chirishdan oldin olinadi
+	ref := entryLogRef(section.ID, title)
+	if err := store.DeleteEntry(section.ID, title); err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	logUserAction(ctx.User, "Admin: Yozuv o'chirildi", ref)
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("%s: '%s' muvaffaqiyatli o'chirildi.", section.Button(), title))
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	resetUserState(ctx.User.ID)
+}
+
+// O'chirishni bekor qilish
+func handleCancelDeleteCallback(ctx *Context) {
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	resetUserState(ctx.User.ID)
+}
+
+// Tahrirlanadigan yozuvni holatga yozib qo'yish
+func beginEntryEdit(ctx *Context, state string) (Section, string, bool) {
+	section, title, ok := callbackEntry(ctx)
+	if !ok {
+		return Section{}, "", false
+	}
+	ctx.State.State = state
+	ctx.State.TempData["updateSection"] = section.ID
+	ctx.State.TempData["updateTitle"] = title
+	return section, title, true
+}
+
+// Yozuv bio'sini yangilashni boshlash
+func handleUpdateBioCallback(ctx *Context) {
+	if _, title, ok := beginEntryEdit(ctx, STATE_UPDATE_BIO); ok {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' uchun yangi bio matnini kiriting:", title))
+	}
+}
+
+// Yozuv nomini o'zgartirishni boshlash
+func handleRenameEntryCallback(ctx *Context) {
+	if _, title, ok := beginEntryEdit(ctx, STATE_RENAME_ENTRY); ok {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' uchun yangi nom kiriting (kategoriya uchun \"Kategoriya / Nom\" ko'rinishida):", title))
+	}
+}
+
+// Yozuvga video qo'shishni boshlash
+func handleAddVideoCallback(ctx *Context) {
+	if _, title, ok := beginEntryEdit(ctx, STATE_ADD_VIDEO); ok {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' uchun videolar qo'shing (bir nechta bo'lishi mumkin). %s", title, videoInputHint))
+	}
+}
+
+// Tanlash yoki yuborishni yakunlash tugmasi (rollar, bir nechta video)
+const doneButton = "✔️ Tayyor"
+
+// Tanlangan rollar belgi bilan ajraladi
+const selectedRolePrefix = "✅ "
+
+// Yozuv rollarini belgilash klaviaturasi: yozuvdagi rollar ✅ bilan ko'rsatiladi
+func entryRoleKeyboard(sectionID, title string) tgbotapi.ReplyKeyboardMarkup {
+	entry, _, err := store.Entry(sectionID, title)
+	if err != nil {
+		log.Printf("Yozuvni o'qishda xatolik: %v", err)
+	}
+	var buttons []string
+	for _, role := range roleNames(true) {
+		if entry.HasRole(role) {
+			role = selectedRolePrefix + role
+		}
+		buttons = append(buttons, role)
+	}
+	return roleKeyboard(buttons, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(doneButton)))
+}
+
+// Yozuv rollarini yangilashni boshlash
+func handleUpdateRoleCallback(ctx *Context) {
+	if section, title, ok := beginEntryEdit(ctx, STATE_UPDATE_ROLE); ok {
+		msg := tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("'%s' rollarini belgilang (qo'shish yoki olib tashlash uchun rolni bosing), so'ng \"%s\" tugmasini bosing:", title, doneButton))
+		msg.ReplyMarkup = entryRoleKeyboard(section.ID, title)
+		outbox.Send(msg)
+	}
+}
+
+// Tahrirlanayotgan yozuvni o'zgartirish va natijani adminga bildirish
+func editEntry(ctx *Context, action, success string, apply func(entry *Entry)) {
+	sectionID := ctx.State.TempData["updateSection"]
+	title := ctx.State.TempData["updateTitle"]
+
+	err := store.UpdateEntry(sectionID, title, func(entry *Entry, exists bool) error {
+		if !exists {
+			return errNotFound
+		}
+		apply(entry)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errNotFound):
+		sendMessage(ctx.Bot, ctx.ChatID, "Yozuv topilmadi.")
+	case err != nil:
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	default:
+		logUserAction(ctx.User, action, entryLogRef(sectionID, title))
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf(success, title))
+	}
+
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	resetUserState(ctx.User.ID)
+}
+
+// Yozuvning yangi bio'si kiritildi
+func handleUpdateBioInput(ctx *Context) {
+	newBio := ctx.Text
+	editEntry(ctx, "Admin: Yozuv bio yangilandi", "'%s' uchun bio muvaffaqiyatli yangilandi!", func(entry *Entry) {
+		entry.Bio = newBio
+		entry.UpdatedAt = time.Now()
+	})
+}
+
+// Yozuvning yangi nomi kiritildi. Videolar, rollar va ID saqlanadi,
+// foydalanuvchilar holatidagi eski nom ham yangilanadi.
+func handleRenameEntryInput(ctx *Context) {
+	sectionID := ctx.State.TempData["updateSection"]
+	oldTitle := ctx.State.TempData["updateTitle"]
+	newTitle, err := validateTitle(strings.TrimSpace(ctx.Text))
+	if err != nil {
+		sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+		return
+	}
+	if newTitle == oldTitle {
+		sendMessage(ctx.Bot, ctx.ChatID, "Yangi nom eskisi bilan bir xil. Boshqa nom kiriting yoki /cancel yuboring.")
+		return
+	}
+
+	err = store.RenameEntry(sectionID, oldTitle, newTitle)
+	switch {
+	case errors.Is(err, errEntryExists):
+		// Holat saqlanadi - admin boshqa nom kiritishi mumkin
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' nomli yozuv bu bo'limda allaqachon mavjud. Boshqa nom kiriting yoki /cancel yuboring.", newTitle))
+		return
+	case errors.Is(err, errNotFound):
+		sendMessage(ctx.Bot, ctx.ChatID, "Yozuv topilmadi.")
+	case err != nil:
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	default:
+		renameEntryReferences(sectionID, oldTitle, newTitle)
+		details := fmt.Sprintf("%s: %s → %s", sectionID, oldTitle, newTitle)
+		if entry, exists, err := store.Entry(sectionID, newTitle); err == nil && exists && entry.ID != "" {
+			details += " [" + entry.ID + "]"
+		}
+		logUserAction(ctx.User, "Admin: Yozuv nomi o'zgartirildi", details)
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' nomi '%s' ga o'zgartirildi.", oldTitle, newTitle))
+	}
+
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	resetUserState(ctx.User.ID)
+}
+
+// Yozuvga qo'shiladigan video kiritildi. Admin bir nechta video yuborishi
+// mumkin, jarayon "✔️ Tayyor" bosilganda yakunlanadi.
+func handleAddVideoInput(ctx *Context) {
+	if ctx.Text == doneButton {
+		sendAdminMenu(ctx.Bot, ctx.ChatID)
+		resetUserState(ctx.User.ID)
+		return
+	}
+	video, err := videoFromMessage(ctx.Message)
+	if err != nil {
+		sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+		return
+	}
+	if !checkDuplicateVideo(ctx, video.ID) {
+		return
+	}
+
+	sectionID := ctx.State.TempData["updateSection"]
+	title := ctx.State.TempData["updateTitle"]
+	total := 0
+	err = store.UpdateEntry(sectionID, title, func(entry *Entry, exists bool) error {
+		if !exists {
+			return errNotFound
+		}
+		entry.Videos = append(entry.Videos, video)
+		entry.UpdatedAt = time.Now()
+		total = len(entry.Videos)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errNotFound):
+		sendMessage(ctx.Bot, ctx.ChatID, "Yozuv topilmadi.")
+		sendAdminMenu(ctx.Bot, ctx.ChatID)
+		resetUserState(ctx.User.ID)
+		return
+	case err != nil:
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	logUserAction(ctx.User, "Admin: Yozuvga video qo'shildi", fmt.Sprintf("%s, video %s", entryLogRef(sectionID, title), video.ID))
+	keyboard := tgbotapi.NewReplyKeyboard(tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(doneButton)))
+	keyboard.ResizeKeyboard = true
+	msg := tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("✅ '%s' uchun video qo'shildi (jami %d ta). Yana video yuboring yoki \"%s\" tugmasini bosing.", title, total, doneButton))
+	msg.ReplyMarkup = keyboard
+	outbox.Send(msg)
+}
+
+// handleUpdateRoleInput da oxirgi rolni olib tashlashga urinilganda
+// UpdateEntry ni hech narsa yozmasdan to'xtatadi
+var errLastRole = errors.New("yozuvning oxirgi roli")
+
+// Yozuv roli bosildi: rol qo'shiladi yoki olib tashlanadi
+func handleUpdateRoleInput(ctx *Context) {
+	sectionID := ctx.State.TempData["updateSection"]
+	title := ctx.State.TempData["updateTitle"]
+	if ctx.Text == doneButton {
+		entry, _, err := store.Entry(sectionID, title)
+		if err != nil {
+			reportStoreError(ctx.Bot, ctx.ChatID, err)
+			return
+		}
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' rollari saqlandi: %s", title, strings.Join(entry.Roles, ", ")))
+		sendAdminMenu(ctx.Bot, ctx.ChatID)
+		resetUserState(ctx.User.ID)
+		return
+	}
+
+	role := strings.TrimPrefix(ctx.Text, selectedRolePrefix)
+	if !isRole(role, true) {
+		sendMessage(ctx.Bot, ctx.ChatID, "Noto'g'ri rol tanlandi. Iltimos, taqdim etilgan tugmalardan birini tanlang.")
+		return
+	}
+
+	var message string
+	err := store.UpdateEntry(sectionID, title, func(entry *Entry, exists bool) error {
+		if !exists {
+			return errNotFound
+		}
+		if !entry.HasRole(role) {
+			entry.Roles = append(entry.Roles, role)
+			message = fmt.Sprintf("➕ '%s' roli qo'shildi.", role)
+			return nil
+		}
+		// Yozuv hech bo'lmaganda bitta rolda ko'rinishi kerak
+		if len(entry.Roles) == 1 {
+			return errLastRole
+		}
+		var roles []string
+		for _, r := range entry.Roles {
+			if r != role {
+				roles = append(roles, r)
+			}
+		}
+		entry.Roles = roles
+		message = fmt.Sprintf("➖ '%s' roli olib tashlandi.", role)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errNotFound):
+		sendMessage(ctx.Bot, ctx.ChatID, "Yozuv topilmadi.")
+		sendAdminMenu(ctx.Bot, ctx.ChatID)
+		resetUserState(ctx.User.ID)
+		return
+	case errors.Is(err, errLastRole):
+		// Hech narsa o'zgarmadi: yozilmaydi va jurnalga tushmaydi
+		message = "Yozuvda kamida bitta rol bo'lishi kerak."
+	case err != nil:
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	default:
+		logUserAction(ctx.User, "Admin: Yozuv rollari yangilandi", entryLogRef(sectionID, title))
+	}
+
+	msg := tgbotapi.NewMessage(ctx.ChatID, message)
+	msg.ReplyMarkup = entryRoleKeyboard(sectionID, title)
+	outbox.Send(msg)
+}
+
+// Bo'lim nomini tekshirish (exceptID - nomi o'zgartirilayotgan bo'lim)
+func validateSectionName(value, exceptID string) (string, error) {
+	if strings.HasPrefix(value, "/") {
+		return "", errors.New("Nom / belgisi bilan boshlanmasligi kerak.")
+	}
+	if utf8.RuneCountInString(value) > 32 {
+		return "", errors.New("Bo'lim nomi juda uzun (ko'pi bilan 32 ta belgi).")
+	}
+	if isRole(value, true) {
+		return "", errors.New("Bo'lim nomi rol nomi bilan bir xil bo'lmasligi kerak.")
+	}
+	if isReservedText(value) {
+		return "", errors.New("Bu nom menyu tugmasi bilan bir xil. Boshqa nom kiriting.")
+	}
+	sections, err := store.Sections()
+	if err != nil {
+		return "", err
+	}
+	for _, section := range sections {
+		if section.ID != exceptID && strings.EqualFold(section.Name, value) {
+			return "", errors.New("Bunday nomli bo'lim allaqachon mavjud.")
+		}
+	}
+	return value, nil
+}
+
+// Bo'lim emojisini tekshirish. Qiymat bo'sh (emoji yo'q) yoki bitta emoji
+// bo'lishi kerak: kamida bitta piktogramma (unicode.So) va faqat emojini
+// tashkil etuvchi belgilar - teri rangi (Sk), variant selektori va keycap
+// (Mn, Me), ZWJ. Harf, raqam va ":)" kabi matnlar tugma nomiga tushib qolardi.
+func validateSectionEmoji(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	invalid := errors.New("Iltimos, bitta emoji yuboring.")
+	if utf8.RuneCountInString(value) > 8 {
+		return "", invalid
+	}
+	pictographs := 0
+	for _, r := range value {
+		switch {
+		case r < utf8.RuneSelf:
+			return "", invalid
+		case unicode.Is(unicode.So, r):
+			pictographs++
+		case unicode.In(r, unicode.Sk, unicode.Mn, unicode.Me), r == '\u200d':
+		default:
+			return "", invalid
+		}
+	}
+	if pictographs == 0 {
+		return "", invalid
+	}
+	return value, nil
+}
+
+// Yangi bo'lim qo'shish ustasi
+var createSectionWizard = &wizard{
+	name:    "create_section",
+	heading: "🗂 Yangi bo'lim:",
+	steps: []wizardStep{
+		{
+			key:      "name",
+			label:    "Bo'lim nomi",
+			prompt:   func(data map[string]string) string { return "Yangi bo'lim nomini kiriting (masalan, Builds):" },
+			validate: func(value string) (string, error) { return validateSectionName(value, "") },
+		},
+		{
+			key:      "emoji",
+			label:    "Emoji",
+			optional: true,
+			prompt: func(data map[string]string) string {
+				return fmt.Sprintf("'%s' bo'limi tugmasi uchun emoji yuboring:", data["name"])
+			},
+			validate: validateSectionEmoji,
+		},
+	},
+	finish: finishCreateSection,
+}
+
+// Yangi bo'limni saqlash va menyuga qo'shish
+func finishCreateSection(ctx *Context, data map[string]string) error {
+	sections, err := store.Sections()
+	if err != nil {
+		return err
+	}
+
+	taken := make(map[string]bool, len(sections))
+	position := 0
+	for _, section := range sections {
+		taken[section.ID] = true
+		if section.Position > position {
+			position = section.Position
+		}
+	}
+	section := Section{
+		ID:       sectionSlug(data["name"], func(id string) bool { return taken[id] }),
+		Name:     data["name"],
+		Emoji:    data["emoji"],
+		Position: position + 1,
+	}
+	if err := store.SaveSection(section); err != nil {
+		return err
+	}
+
+	logUserAction(ctx.User, "Admin: Yangi bo'lim qo'shildi", section.ID)
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("✅ %s bo'limi qo'shildi. U asosiy menyuda ko'rinadi.", section.Button()))
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	return nil
+}
+
+// "🗂 Bo'limlar" tugmasi
+func handleSectionsButton(ctx *Context) {
+	logUserAction(ctx.User, "Admin: Bo'limlar ro'yxatini so'radi", "")
+	showSectionsForAdmin(ctx)
+}
+
+// Admin uchun bo'limlarni boshqarish menyusini ko'rsatish
+func showSectionsForAdmin(ctx *Context) {
+	sections, err := store.Sections()
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	for i, section := range sections {
+		entries, err := store.Entries(section.ID)
+		if err != nil {
+			reportStoreError(ctx.Bot, ctx.ChatID, err)
+			return
+		}
+
+		row := tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Nom", "rename_section:"+section.ID),
+			tgbotapi.NewInlineKeyboardButtonData("😀 Emoji", "section_emoji:"+section.ID),
+		)
+		if i > 0 {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData("⬆️ Yuqoriga", "move_section:"+section.ID))
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("❌ O'chirish", "delete_section:"+section.ID))
+
+		msg := tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("%d. %s — %d ta yozuv", i+1, section.Button(), len(entries)))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+		outbox.Send(msg)
+	}
+
+	text := "Yangi bo'lim qo'shish uchun tugmani bosing."
+	if len(sections) == 0 {
+		text = "Hozircha bo'limlar mavjud emas. " + text
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Yangi bo'lim", "add_section"),
+		),
+	)
+	outbox.Send(msg)
+}
+
+// Yangi bo'lim qo'shishni boshlash
+func handleAddSectionCallback(ctx *Context) {
+	logUserAction(ctx.User, "Admin: Yangi bo'lim qo'shish boshlandi", "")
+	createSectionWizard.start(ctx)
+}
+
+// Tahrirlanadigan bo'limni holatga yozib qo'yish
+func beginSectionEdit(ctx *Context, state string) (Section, bool) {
+	section, ok := callbackSection(ctx)
+	if !ok {
+		return Section{}, false
+	}
+	ctx.State.State = state
+	ctx.State.TempData["updateSection"] = section.ID
+	ctx.State.TempData["updateTitle"] = section.Name
+	return section, true
+}
+
+// Bo'lim nomini o'zgartirishni boshlash
+func handleRenameSectionCallback(ctx *Context) {
+	if section, ok := beginSectionEdit(ctx, STATE_RENAME_SECTION); ok {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' bo'limi uchun yangi nom kiriting:", section.Name))
+	}
+}
+
+// Bo'lim emojisini o'zgartirishni boshlash
+func handleSectionEmojiCallback(ctx *Context) {
+	if section, ok := beginSectionEdit(ctx, STATE_SECTION_EMOJI); ok {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' bo'limi uchun yangi emoji yuboring (olib tashlash uchun \"-\"):", section.Name))
+	}
+}
+
+// Tahrirlanayotgan bo'limni o'zgartirish va natijani adminga bildirish
+func editSection(ctx *Context, action, success string, apply func(section *Section)) {
+	section, exists, err := store.Section(ctx.State.TempData["updateSection"])
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !exists {
+		sendMessage(ctx.Bot, ctx.ChatID, "Bo'lim topilmadi.")
+	} else {
+		apply(&section)
+		if err := store.SaveSection(section); err != nil {
+			reportStoreError(ctx.Bot, ctx.ChatID, err)
+			return
+		}
+		logUserAction(ctx.User, action, section.ID)
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf(success, section.Button()))
+	}
+
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	resetUserState(ctx.User.ID)
+}
+
+// Bo'limning yangi nomi kiritildi
+func handleRenameSectionInput(ctx *Context) {
+	name, err := validateSectionName(strings.TrimSpace(ctx.Text), ctx.State.TempData["updateSection"])
+	if err == nil && name == "" {
+		err = errors.New("Iltimos, matn ko'rinishida nom kiriting.")
+	}
+	if err != nil {
+		sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+		return
+	}
+	editSection(ctx, "Admin: Bo'lim nomi o'zgartirildi", "✅ Bo'lim nomi o'zgartirildi: %s", func(section *Section) {
+		section.Name = name
+	})
+}
+
+// Bo'limning yangi emojisi kiritildi
+func handleSectionEmojiInput(ctx *Context) {
+	emoji := strings.TrimSpace(ctx.Text)
+	if emoji == "-" {
+		emoji = ""
+	}
+	emoji, err := validateSectionEmoji(emoji)
+	if err != nil {
+		sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+		return
+	}
+	editSection(ctx, "Admin: Bo'lim emojisi o'zgartirildi", "✅ Bo'lim tugmasi yangilandi: %s", func(section *Section) {
+		section.Emoji = emoji
+	})
+}
+
+// Bo'limni menyuda bir pog'ona yuqoriga ko'tarish
+func handleMoveSectionCallback(ctx *Context) {
+	section, ok := callbackSection(ctx)
+	if !ok {
+		return
+	}
+	sections, err := store.Sections()
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	for i := 1; i < len(sections); i++ {
+		if sections[i].ID != section.ID {
+			continue
+		}
+		sections[i-1], sections[i] = sections[i], sections[i-1]
+		// Tartib raqamlarini qaytadan berish (bir xil raqamlar bo'lsa ham to'g'ri ishlaydi)
+		for position := range sections {
+			if sections[position].Position == position+1 {
+				continue
+			}
+			sections[position].Position = position + 1
+			if err := store.SaveSection(sections[position]); err != nil {
+				reportStoreError(ctx.Bot, ctx.ChatID, err)
+				return
+			}
+		}
+		logUserAction(ctx.User, "Admin: Bo'lim tartibi o'zgartirildi", section.ID)
+		break
+	}
+
+	showSectionsForAdmin(ctx)
+}
+
+// Bo'limni o'chirishni tasdiqlash so'rovi
+func handleDeleteSectionCallback(ctx *Context) {
+	section, ok := callbackSection(ctx)
+	if !ok {
+		return
+	}
+	entries, err := store.Entries(section.ID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	ctx.State.State = STATE_CONFIRM_DELETE_SECTION
+
+	text := fmt.Sprintf("%s bo'limini o'chirishni tasdiqlaysizmi?", section.Button())
+	if len(entries) > 0 {
+		text += fmt.Sprintf("\n\n⚠️ Bo'limdagi %d ta yozuv ham o'chiriladi.", len(entries))
+	}
+
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Ha, o'chirish", "confirm_delete_section:"+section.ID),
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Bekor qilish", "cancel_delete"),
+		),
+	)
+	outbox.Send(msg)
+}
+
+// Bo'limni yozuvlari bilan birga o'chirish
+func handleConfirmDeleteSectionCallback(ctx *Context) {
+	section, ok := callbackSection(ctx)
+	if !ok {
+		return
+	}
+
+	if err := store.DeleteSection(section.ID); err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	logUserAction(ctx.User, "Admin: Bo'lim o'chirildi", section.ID)
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("%s bo'limi muvaffaqiyatli o'chirildi.", section.Button()))
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	resetUserState(ctx.User.ID)
+}
@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestValidateSectionEmoji(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"", true},
+		{"📚", true},
+		{"🛠️", true},   // variant selektori bilan
+		{"👍🏽", true},   // teri rangi bilan
+		{"👨‍💻", true},  // ZWJ ketma-ketligi
+		{"🇺🇿", true},   // bayroq
+		{"abc", false}, // harflar
+		{"123", false}, // raqamlar
+		{":)", false},  // ASCII smaylik
+		{"📚a", false},  // emoji va harf
+		{"Ж", false},   // ASCII bo'lmagan harf
+		{"📚 📖", false}, // bo'sh joy
+		{"📚📚📚📚📚📚📚📚📚", false}, // juda uzun
+	}
+	for _, tt := range tests {
+		_, err := validateSectionEmoji(tt.value)
+		if (err == nil) != tt.valid {
+			t.Errorf("validateSectionEmoji(%q) xatoligi = %v, valid %v", tt.value, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateSectionName(t *testing.T) {
+	setupTestBot(t)
+	newBotRouter()
+
+	tests := []struct {
+		name, value, exceptID string
+		valid                 bool
+	}{
+		{"yangi nom", "Builds", "", true},
+		{"buyruqqa o'xshash", "/builds", "", false},
+		{"juda uzun", "Bo'lim nomi o'ttiz ikki belgidan uzunroq", "", false},
+		{"rol nomi", "Tank", "", false},
+		{"menyu tugmasi", "Orqaga", "", false},
+		{"menyu tugmasi emoji bilan", "⬅️ Orqaga", "", false},
+		{"mavjud bo'lim", "tutorials", "", false},
+		{"o'z nomini saqlash", "Tutorials", "tutorials", true},
+	}
+	for _, tt := range tests {
+		_, err := validateSectionName(tt.value, tt.exceptID)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: validateSectionName(%q) xatoligi = %v, valid %v", tt.name, tt.value, err, tt.valid)
+		}
+	}
+}
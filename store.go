@@ -0,0 +1,131 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Store - bot ma'lumotlari ombori interfeysi.
+// Handlerlar faqat shu interfeys orqali ishlaydi, shuning uchun JSON fayl
+// yoki SQLite bazasini ishga tushirishda tanlash mumkin.
+type Store interface {
+	// Kontent bo'limlari (menyudagi tartibda)
+	Sections() ([]Section, error)
+	Section(id string) (Section, bool, error)
+	SaveSection(section Section) error
+	// DeleteSection bo'limni barcha yozuvlari bilan birga o'chiradi
+	DeleteSection(id string) error
+
+	// Rollar (klaviaturadagi tartibda)
+	Roles() ([]Role, error)
+	SaveRole(role Role) error
+	// RenameRole rol nomini shu roldagi barcha yozuvlar bilan birga o'zgartiradi.
+	// Rol mavjud bo'lmasa errNotFound, yangi nom band bo'lsa errRoleExists qaytaradi.
+	RenameRole(oldName, newName string) error
+	DeleteRole(name string) error
+
+	// Bo'lim yozuvlari
+	Entries(sectionID string) (map[string]Entry, error)
+	Entry(sectionID, title string) (Entry, bool, error)
+	// FindEntry yozuvni qisqa ID si bo'yicha topadi (callback va havolalar uchun)
+	FindEntry(id string) (sectionID, title string, found bool, err error)
+	SaveEntry(sectionID, title string, entry Entry) error
+	// UpdateEntry o'qish-o'zgartirish-yozish siklini bitta qulf ostida bajaradi.
+	// Yangi yozuvga ID beriladi, mavjud yozuv ID si o'zgarmaydi.
+	// Bo'lim mavjud bo'lmasa errNotFound qaytaradi.
+	UpdateEntry(sectionID, title string, apply func(entry *Entry, exists bool) error) error
+	// RenameEntry yozuv nomini o'zgartiradi (ID va boshqa maydonlar saqlanadi).
+	// Yozuv topilmasa errNotFound, yangi nom band bo'lsa errEntryExists qaytaradi.
+	RenameEntry(sectionID, oldTitle, newTitle string) error
+	DeleteEntry(sectionID, title string) error
+
+	// Adminlar
+	Admins() (map[string]AdminInfo, error)
+	IsAdmin(username string) (bool, error)
+	SaveAdmin(admin AdminInfo) error
+	DeleteAdmin(username string) error
+
+	// Foydalanuvchilar
+	SaveUser(user UserInfo) error
+	User(id int64) (UserInfo, bool, error)
+	Users() ([]UserInfo, error)
+
+	// Tugallanmagan jarayonlar (qayta ishga tushishdan keyin tiklash uchun)
+	SaveUserState(state UserState) error
+	DeleteUserState(userID int64) error
+	UserStates() ([]UserState, error)
+
+	// Foydalanuvchi harakatlari
+	AddAction(action UserAction) error
+	Actions() ([]UserAction, error)
+
+	Close() error
+}
+
+// Update funksiyalarida yozuv topilmaganda qaytariladi
+var errNotFound = errors.New("yozuv topilmadi")
+
+// RenameEntry da yangi nom bilan yozuv allaqachon mavjud bo'lsa qaytariladi
+var errEntryExists = errors.New("bu nomli yozuv allaqachon mavjud")
+
+// RenameRole da yangi nom bilan rol allaqachon mavjud bo'lsa qaytariladi
+var errRoleExists = errors.New("bu nomli rol allaqachon mavjud")
+
+// Bo'sh yozuv ID sini qidirishdagi urinishlar soni. 2^32 ta ID ichida
+// to'qnashuv juda kam bo'ladi, shuncha urinish yetmasa - nimadir buzilgan.
+const entryIDAttempts = 10
+
+// Yangi yozuv ID si - 8 ta hex belgi. ID yozuv yaratilganda bir marta beriladi
+// va callback ma'lumotlari (64 bayt), havolalar hamda jurnallarda nom o'rniga
+// ishlatiladi: nom uzun, kirillcha yoki ":" belgili bo'lsa ham muammo bo'lmaydi.
+// taken xatoligi (masalan, ombordan o'qish) o'zgarishsiz qaytariladi.
+func newEntryID(taken func(id string) (bool, error)) (string, error) {
+	for i := 0; i < entryIDAttempts; i++ {
+		var buf [4]byte
+		if _, err := rand.Read(buf[:]); err != nil {
+			return "", fmt.Errorf("tasodifiy ID yaratishda xatolik: %w", err)
+		}
+		id := hex.EncodeToString(buf[:])
+		used, err := taken(id)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("%d ta urinishda bo'sh yozuv ID si topilmadi", entryIDAttempts)
+}
+
+// Omborni tanlangan drayver bo'yicha ochish
+func openStore(driver string) (Store, error) {
+	switch driver {
+	case "json":
+		return openJSONStore(cfg.DataFile)
+	case "sqlite":
+		return openSQLiteStore(cfg.SQLiteFile, cfg.DataFile)
+	default:
+		return nil, fmt.Errorf("noma'lum ombor turi: %q (json yoki sqlite bo'lishi kerak)", driver)
+	}
+}
+
+// Ombor xatoligini qayd qilish va foydalanuvchiga xabar berish
+func reportStoreError(bot *tgbotapi.BotAPI, chatID int64, err error) {
+	log.Printf("Ma'lumotlar omborida xatolik: %v", err)
+	sendMessage(bot, chatID, "Ma'lumotlar bilan ishlashda xatolik yuz berdi. Keyinroq qayta urinib ko'ring.")
+}
+
+// Telegram foydalanuvchisini saqlash uchun tuzilmaga o'tkazish
+func userInfoFrom(user *tgbotapi.User) UserInfo {
+	return UserInfo{
+		ID:        user.ID,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Username:  user.UserName,
+	}
+}
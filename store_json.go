@@ -0,0 +1,466 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// jsonStore - barcha ma'lumotlarni bitta JSON faylda saqlovchi ombor.
+// O'qish xotiradagi nusxadan bajariladi. Har bir o'zgarish mu qulfi ostida
+// faylni qayta o'qiydi, o'zgartiradi va vaqtinchalik fayl orqali atomik yozadi.
+type jsonStore struct {
+	mu   sync.Mutex
+	path string
+	data BotData
+	// Oxirgi o'qishda fayl buzilgan bo'lsa, uni ustidan yozish taqiqlanadi
+	loadErr error
+	// Harakatlar faylga yozilmaydi, ular user_logs papkasidagi log fayllarida qoladi
+	actions []UserAction
+}
+
+// JSON omborni ochish. Fayl buzilgan bo'lsa, bot bo'sh katalog bilan ishga
+// tushadi, lekin fayl tuzatilmaguncha unga hech narsa yozilmaydi.
+func openJSONStore(path string) (*jsonStore, error) {
+	s := &jsonStore{path: path, data: emptyBotData()}
+	reports, err := s.load()
+	if err != nil {
+		log.Printf("Diqqat: %v. Fayl tuzatilmaguncha o'zgarishlar saqlanmaydi.", err)
+		return s, nil
+	}
+
+	// Eski versiyadagi fayl yangilangan bo'lsa, avval nusxa olib, keyin saqlaymiz
+	if len(reports) > 0 {
+		if err := s.backupBeforeMigration(reports[0].version - 1); err != nil {
+			return nil, err
+		}
+		if err := s.save(); err != nil {
+			return nil, err
+		}
+		log.Printf("%s migratsiya qilindi: %s", path, describeMigrations(reports))
+	}
+	return s, nil
+}
+
+// Migratsiyadan oldingi faylning nusxasini saqlash
+func (s *jsonStore) backupBeforeMigration(fromVersion int) error {
+	fileData, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("migratsiya nusxasini olishda xatolik: %w", err)
+	}
+	backupPath := fmt.Sprintf("%s.v%d.bak", s.path, fromVersion)
+	if err := writeFileAtomic(backupPath, fileData, 0644); err != nil {
+		return fmt.Errorf("migratsiya nusxasini yozishda xatolik: %w", err)
+	}
+	return nil
+}
+
+// Faylni o'qish va kerak bo'lsa joriy sxemaga migratsiya qilish.
+// Xatolik bo'lsa, xotiradagi ma'lumotlar o'zgarmaydi.
+func (s *jsonStore) load() ([]migrationReport, error) {
+	fileData, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		// Fayl hali yaratilmagan - xotiradagi ma'lumotlar bilan davom etamiz
+		s.loadErr = nil
+		return nil, nil
+	}
+	if err != nil {
+		s.loadErr = fmt.Errorf("%s faylini o'qishda xatolik: %w", s.path, err)
+		return nil, s.loadErr
+	}
+
+	data, reports, err := decodeBotData(fileData)
+	if err != nil {
+		s.loadErr = fmt.Errorf("%s faylini o'qishda xatolik: %w", s.path, err)
+		return nil, s.loadErr
+	}
+
+	s.data = data
+	s.loadErr = nil
+	return reports, nil
+}
+
+// Faylga atomik yozish: avval vaqtinchalik faylga, keyin rename orqali almashtirish
+func (s *jsonStore) save() error {
+	if s.loadErr != nil {
+		return fmt.Errorf("fayl buzilganligi sababli saqlash rad etildi: %w", s.loadErr)
+	}
+
+	s.data.SchemaVersion = currentSchemaVersion
+	fileData, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON kodlashda xatolik: %w", err)
+	}
+	if err := writeFileAtomic(s.path, fileData, 0644); err != nil {
+		return fmt.Errorf("%s fayliga yozishda xatolik: %w", s.path, err)
+	}
+	return nil
+}
+
+// O'qish-o'zgartirish-yozish sikli. Boshqa jarayon yoki qo'lda kiritilgan
+// o'zgarishlar yo'qolmasligi uchun fayl har safar qayta o'qiladi.
+func (s *jsonStore) update(apply func(data *BotData) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.load(); err != nil {
+		return fmt.Errorf("fayl buzilganligi sababli saqlash rad etildi: %w", err)
+	}
+	if err := apply(&s.data); err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// Faylni atomik almashtirish. Yozish o'rtasida jarayon to'xtasa ham
+// eski fayl butunligicha qoladi.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	// Xatolik bo'lsa vaqtinchalik faylni tozalash
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+
+	// Rename papka yozuvida saqlanishi uchun papkani ham sinxronlash
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+func (s *jsonStore) Sections() ([]Section, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sections := make([]Section, 0, len(s.data.Sections))
+	for _, section := range s.data.Sections {
+		sections = append(sections, section)
+	}
+	sortSections(sections)
+	return sections, nil
+}
+
+func (s *jsonStore) Section(id string) (Section, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	section, exists := s.data.Sections[id]
+	return section, exists, nil
+}
+
+func (s *jsonStore) SaveSection(section Section) error {
+	return s.update(func(data *BotData) error {
+		data.Sections[section.ID] = section
+		return nil
+	})
+}
+
+func (s *jsonStore) DeleteSection(id string) error {
+	return s.update(func(data *BotData) error {
+		delete(data.Sections, id)
+		delete(data.Entries, id)
+		return nil
+	})
+}
+
+func (s *jsonStore) Roles() ([]Role, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	roles := make([]Role, 0, len(s.data.Roles))
+	for _, role := range s.data.Roles {
+		roles = append(roles, role)
+	}
+	sortRoles(roles)
+	return roles, nil
+}
+
+func (s *jsonStore) SaveRole(role Role) error {
+	return s.update(func(data *BotData) error {
+		data.Roles[role.Name] = role
+		return nil
+	})
+}
+
+func (s *jsonStore) RenameRole(oldName, newName string) error {
+	return s.update(func(data *BotData) error {
+		role, exists := data.Roles[oldName]
+		if !exists {
+			return errNotFound
+		}
+		if _, taken := data.Roles[newName]; taken && newName != oldName {
+			return errRoleExists
+		}
+		delete(data.Roles, oldName)
+		role.Name = newName
+		data.Roles[newName] = role
+
+		for _, entries := range data.Entries {
+			for _, entry := range entries {
+				for i := range entry.Roles {
+					if entry.Roles[i] == oldName {
+						entry.Roles[i] = newName
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *jsonStore) DeleteRole(name string) error {
+	return s.update(func(data *BotData) error {
+		delete(data.Roles, name)
+		return nil
+	})
+}
+
+func (s *jsonStore) Entries(sectionID string) (map[string]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make(map[string]Entry, len(s.data.Entries[sectionID]))
+	for title, entry := range s.data.Entries[sectionID] {
+		entries[title] = entry
+	}
+	return entries, nil
+}
+
+func (s *jsonStore) Entry(sectionID, title string) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.data.Entries[sectionID][title]
+	return entry, exists, nil
+}
+
+func (s *jsonStore) SaveEntry(sectionID, title string, entry Entry) error {
+	return s.UpdateEntry(sectionID, title, func(existing *Entry, exists bool) error {
+		*existing = entry
+		return nil
+	})
+}
+
+func (s *jsonStore) UpdateEntry(sectionID, title string, apply func(entry *Entry, exists bool) error) error {
+	return s.update(func(data *BotData) error {
+		if _, exists := data.Sections[sectionID]; !exists {
+			return errNotFound
+		}
+		entries := data.Entries[sectionID]
+		if entries == nil {
+			entries = make(map[string]Entry)
+			data.Entries[sectionID] = entries
+		}
+		entry, exists := entries[title]
+		previousID := entry.ID
+		if err := apply(&entry, exists); err != nil {
+			return err
+		}
+		if previousID != "" {
+			entry.ID = previousID
+		}
+		if entry.ID == "" {
+			id, err := newEntryID(func(id string) (bool, error) {
+				_, _, found := data.findEntry(id)
+				return found, nil
+			})
+			if err != nil {
+				return err
+			}
+			entry.ID = id
+		}
+		entries[title] = entry
+		return nil
+	})
+}
+
+func (s *jsonStore) FindEntry(id string) (string, string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sectionID, title, found := s.data.findEntry(id)
+	return sectionID, title, found, nil
+}
+
+// Yozuvni ID bo'yicha qidirish
+func (data *BotData) findEntry(id string) (string, string, bool) {
+	for sectionID, entries := range data.Entries {
+		for title, entry := range entries {
+			if entry.ID == id {
+				return sectionID, title, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func (s *jsonStore) RenameEntry(sectionID, oldTitle, newTitle string) error {
+	return s.update(func(data *BotData) error {
+		entries := data.Entries[sectionID]
+		entry, exists := entries[oldTitle]
+		if !exists {
+			return errNotFound
+		}
+		if _, taken := entries[newTitle]; taken {
+			return errEntryExists
+		}
+		delete(entries, oldTitle)
+		entries[newTitle] = entry
+		return nil
+	})
+}
+
+func (s *jsonStore) DeleteEntry(sectionID, title string) error {
+	return s.update(func(data *BotData) error {
+		delete(data.Entries[sectionID], title)
+		return nil
+	})
+}
+
+func (s *jsonStore) Admins() (map[string]AdminInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	admins := make(map[string]AdminInfo, len(s.data.Admins))
+	for username, info := range s.data.Admins {
+		admins[username] = info
+	}
+	return admins, nil
+}
+
+func (s *jsonStore) IsAdmin(username string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.data.Admins[username]
+	return exists, nil
+}
+
+func (s *jsonStore) SaveAdmin(admin AdminInfo) error {
+	return s.update(func(data *BotData) error {
+		data.Admins[admin.Username] = admin
+		return nil
+	})
+}
+
+func (s *jsonStore) DeleteAdmin(username string) error {
+	return s.update(func(data *BotData) error {
+		delete(data.Admins, username)
+		return nil
+	})
+}
+
+func (s *jsonStore) SaveUser(user UserInfo) error {
+	key := strconv.FormatInt(user.ID, 10)
+
+	// O'zgarmagan foydalanuvchi uchun faylni qayta yozmaymiz
+	s.mu.Lock()
+	existing, exists := s.data.Users[key]
+	s.mu.Unlock()
+	if exists && existing == user {
+		return nil
+	}
+
+	return s.update(func(data *BotData) error {
+		data.Users[key] = user
+		return nil
+	})
+}
+
+func (s *jsonStore) User(id int64) (UserInfo, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.data.Users[strconv.FormatInt(id, 10)]
+	return user, exists, nil
+}
+
+func (s *jsonStore) Users() ([]UserInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make([]UserInfo, 0, len(s.data.Users))
+	for _, user := range s.data.Users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// Holatlar TempData nusxasi bilan saqlanadi va qaytariladi: ishchilar o'z
+// xaritasini qulfsiz o'zgartiradi, ombordagi nusxa esa boshqa goroutinelarda o'qiladi
+func (s *jsonStore) SaveUserState(state UserState) error {
+	state.TempData = maps.Clone(state.TempData)
+	return s.update(func(data *BotData) error {
+		data.UserStates[strconv.FormatInt(state.UserID, 10)] = state
+		return nil
+	})
+}
+
+func (s *jsonStore) DeleteUserState(userID int64) error {
+	return s.update(func(data *BotData) error {
+		delete(data.UserStates, strconv.FormatInt(userID, 10))
+		return nil
+	})
+}
+
+func (s *jsonStore) UserStates() ([]UserState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]UserState, 0, len(s.data.UserStates))
+	for _, state := range s.data.UserStates {
+		state.TempData = maps.Clone(state.TempData)
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func (s *jsonStore) AddAction(action UserAction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.actions = append(s.actions, action)
+	return nil
+}
+
+func (s *jsonStore) Actions() ([]UserAction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	actions := make([]UserAction, len(s.actions))
+	copy(actions, s.actions)
+	return actions, nil
+}
+
+func (s *jsonStore) Close() error {
+	return nil
+}
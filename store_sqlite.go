@@ -0,0 +1,711 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteStore - ichki (pure-Go) SQLite bazasidagi ombor
+type sqliteStore struct {
+	db *sql.DB
+}
+
+// *sql.DB va *sql.Tx uchun umumiy interfeys
+type sqlRunner interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// Boshlang'ich sxema (1-migratsiya)
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS tutorials (
+	title  TEXT PRIMARY KEY,
+	bio    TEXT NOT NULL DEFAULT '',
+	role   TEXT NOT NULL DEFAULT '',
+	videos TEXT NOT NULL DEFAULT '[]'
+);
+CREATE TABLE IF NOT EXISTS stories (
+	title  TEXT PRIMARY KEY,
+	bio    TEXT NOT NULL DEFAULT '',
+	role   TEXT NOT NULL DEFAULT '',
+	videos TEXT NOT NULL DEFAULT '[]'
+);
+CREATE TABLE IF NOT EXISTS admins (
+	username TEXT PRIMARY KEY,
+	added_by TEXT NOT NULL DEFAULT '',
+	added_at TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS users (
+	id         INTEGER PRIMARY KEY,
+	first_name TEXT NOT NULL DEFAULT '',
+	last_name  TEXT NOT NULL DEFAULT '',
+	username   TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS actions (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id    INTEGER NOT NULL,
+	username   TEXT NOT NULL DEFAULT '',
+	first_name TEXT NOT NULL DEFAULT '',
+	last_name  TEXT NOT NULL DEFAULT '',
+	action     TEXT NOT NULL DEFAULT '',
+	details    TEXT NOT NULL DEFAULT '',
+	created_at TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS actions_user_id ON actions(user_id);
+`
+
+// SQLite omborni ochish. Yangi bazaga JSON fayldagi mavjud ma'lumotlar
+// (fayl bo'lsa) bir marta import qilinadi.
+func openSQLiteStore(path, importFile string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("SQLite bazasini ochishda xatolik: %w", err)
+	}
+	// SQLite bir vaqtda faqat bitta yozuvchini qo'llab-quvvatlaydi. Yagona ulanish
+	// tranzaksiyalarni ham ketma-ket bajarilishga majbur qiladi.
+	db.SetMaxOpenConns(1)
+
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("SQLite sxemasini yangilashda xatolik: %w", err)
+	}
+
+	s := &sqliteStore{db: db}
+	if err := s.importJSON(importFile); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// JSON fayldagi ma'lumotlarni yangi bazaga bir marta ko'chirish.
+// Import bitta tranzaksiyada bajariladi va meta jadvalida belgilanadi: yarim
+// qolgan import saqlanmaydi, tugagani esa (yozuvlar bo'lmasa ham) takrorlanmaydi.
+func (s *sqliteStore) importJSON(path string) error {
+	var imported bool
+	err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM meta WHERE key = 'json_import')`).Scan(&imported)
+	if err != nil {
+		return fmt.Errorf("SQLite bazasini tekshirishda xatolik: %w", err)
+	}
+	if imported {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("tranzaksiyani boshlashda xatolik: %w", err)
+	}
+	defer tx.Rollback()
+
+	fileData, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		// Import qilinadigan fayl yo'q - baza bo'sh holda boshlanadi
+	case err != nil:
+		return fmt.Errorf("JSON ma'lumotlarini import qilishda xatolik: %w", err)
+	default:
+		if err := importBotData(tx, path, fileData); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`INSERT INTO meta (key, value) VALUES ('json_import', ?)`, path); err != nil {
+		return fmt.Errorf("meta jadvaliga yozishda xatolik: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tranzaksiyani yakunlashda xatolik: %w", err)
+	}
+	return nil
+}
+
+// JSON fayl mazmunini tranzaksiya ichida bazaga yozish
+func importBotData(tx *sql.Tx, path string, fileData []byte) error {
+	// Manba fayl o'zgartirilmaydi - migratsiya faqat xotirada bajariladi
+	source, _, err := decodeBotData(fileData)
+	if err != nil {
+		return fmt.Errorf("JSON ma'lumotlarini import qilishda xatolik: %w", err)
+	}
+	// Migratsiyadagi standart rollar fayldagi ro'yxat bilan almashtiriladi,
+	// aks holda fayldagi nomi o'zgartirilgan rollar eski nomi bilan ham qolardi
+	if _, err := tx.Exec(`DELETE FROM roles`); err != nil {
+		return fmt.Errorf("roles jadvalini tozalashda xatolik: %w", err)
+	}
+	for _, role := range source.Roles {
+		if err := saveRole(tx, role); err != nil {
+			return err
+		}
+	}
+	entryCount := 0
+	for _, section := range source.Sections {
+		if err := saveSection(tx, section); err != nil {
+			return err
+		}
+		for title, entry := range source.Entries[section.ID] {
+			if entry.ID == "" {
+				id, err := newEntryID(func(id string) (bool, error) {
+					_, _, found, err := entryByID(tx, id)
+					return found, err
+				})
+				if err != nil {
+					return err
+				}
+				entry.ID = id
+			}
+			if err := saveEntry(tx, section.ID, title, entry); err != nil {
+				return err
+			}
+			entryCount++
+		}
+	}
+	for _, admin := range source.Admins {
+		if err := saveAdmin(tx, admin); err != nil {
+			return err
+		}
+	}
+	for _, user := range source.Users {
+		if err := saveUser(tx, user); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("%s faylidan %d ta bo'lim, %d ta yozuv va %d ta admin import qilindi",
+		path, len(source.Sections), entryCount, len(source.Admins))
+	return nil
+}
+
+func (s *sqliteStore) Sections() ([]Section, error) {
+	rows, err := s.db.Query(`SELECT id, name, emoji, position FROM sections ORDER BY position, id`)
+	if err != nil {
+		return nil, fmt.Errorf("sections jadvalini o'qishda xatolik: %w", err)
+	}
+	defer rows.Close()
+
+	var sections []Section
+	for rows.Next() {
+		var section Section
+		if err := rows.Scan(&section.ID, &section.Name, &section.Emoji, &section.Position); err != nil {
+			return nil, fmt.Errorf("sections jadvalini o'qishda xatolik: %w", err)
+		}
+		sections = append(sections, section)
+	}
+	return sections, rows.Err()
+}
+
+func loadSection(db sqlRunner, id string) (Section, bool, error) {
+	section := Section{ID: id}
+	err := db.QueryRow(`SELECT name, emoji, position FROM sections WHERE id = ?`, id).
+		Scan(&section.Name, &section.Emoji, &section.Position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Section{}, false, nil
+	}
+	if err != nil {
+		return Section{}, false, fmt.Errorf("sections jadvalini o'qishda xatolik: %w", err)
+	}
+	return section, true, nil
+}
+
+func (s *sqliteStore) Section(id string) (Section, bool, error) {
+	return loadSection(s.db, id)
+}
+
+func (s *sqliteStore) SaveSection(section Section) error {
+	return saveSection(s.db, section)
+}
+
+func saveSection(db sqlRunner, section Section) error {
+	_, err := db.Exec(`INSERT INTO sections (id, name, emoji, position) VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET name = excluded.name, emoji = excluded.emoji, position = excluded.position`,
+		section.ID, section.Name, section.Emoji, section.Position)
+	if err != nil {
+		return fmt.Errorf("sections jadvaliga yozishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteSection(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("tranzaksiyani boshlashda xatolik: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM entries WHERE section_id = ?`, id); err != nil {
+		return fmt.Errorf("entries jadvalidan o'chirishda xatolik: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM sections WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("sections jadvalidan o'chirishda xatolik: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tranzaksiyani yakunlashda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Roles() ([]Role, error) {
+	rows, err := s.db.Query(`SELECT name, position, hidden FROM roles ORDER BY position, name`)
+	if err != nil {
+		return nil, fmt.Errorf("roles jadvalini o'qishda xatolik: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []Role
+	for rows.Next() {
+		var role Role
+		if err := rows.Scan(&role.Name, &role.Position, &role.Hidden); err != nil {
+			return nil, fmt.Errorf("roles jadvalini o'qishda xatolik: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (s *sqliteStore) SaveRole(role Role) error {
+	return saveRole(s.db, role)
+}
+
+func saveRole(db sqlRunner, role Role) error {
+	_, err := db.Exec(`INSERT INTO roles (name, position, hidden) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET position = excluded.position, hidden = excluded.hidden`,
+		role.Name, role.Position, role.Hidden)
+	if err != nil {
+		return fmt.Errorf("roles jadvaliga yozishda xatolik: %w", err)
+	}
+	return nil
+}
+
+// Rol nomini va unga bog'langan yozuvlarni bitta tranzaksiyada o'zgartirish
+func (s *sqliteStore) RenameRole(oldName, newName string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("tranzaksiyani boshlashda xatolik: %w", err)
+	}
+	defer tx.Rollback()
+
+	if newName != oldName {
+		var taken int
+		err := tx.QueryRow(`SELECT COUNT(*) FROM roles WHERE name = ?`, newName).Scan(&taken)
+		if err != nil {
+			return fmt.Errorf("roles jadvalini o'qishda xatolik: %w", err)
+		}
+		if taken > 0 {
+			return errRoleExists
+		}
+	}
+	result, err := tx.Exec(`UPDATE roles SET name = ? WHERE name = ?`, newName, oldName)
+	if err != nil {
+		return fmt.Errorf("roles jadvaliga yozishda xatolik: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("roles jadvaliga yozishda xatolik: %w", err)
+	} else if affected == 0 {
+		return errNotFound
+	}
+	_, err = tx.Exec(`UPDATE entries SET roles = (
+			SELECT json_group_array(CASE WHEN value = ?1 THEN ?2 ELSE value END) FROM json_each(entries.roles)
+		) WHERE EXISTS (SELECT 1 FROM json_each(entries.roles) WHERE value = ?1)`, oldName, newName)
+	if err != nil {
+		return fmt.Errorf("entries jadvaliga yozishda xatolik: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tranzaksiyani yakunlashda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteRole(name string) error {
+	if _, err := s.db.Exec(`DELETE FROM roles WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("roles jadvalidan o'chirishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Entries(sectionID string) (map[string]Entry, error) {
+	rows, err := s.db.Query(`SELECT id, title, bio, roles, tags, videos, position, pinned, separate, created_at, updated_at
+		FROM entries WHERE section_id = ?`, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("entries jadvalini o'qishda xatolik: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make(map[string]Entry)
+	for rows.Next() {
+		var title, roles, tags, videos, createdAt, updatedAt string
+		var entry Entry
+		if err := rows.Scan(&entry.ID, &title, &entry.Bio, &roles, &tags, &videos,
+			&entry.Position, &entry.Pinned, &entry.Separate, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("entries jadvalini o'qishda xatolik: %w", err)
+		}
+		entry.CreatedAt, entry.UpdatedAt = parseStoredTime(createdAt), parseStoredTime(updatedAt)
+		if err := decodeEntryLists(&entry, roles, tags, videos); err != nil {
+			return nil, fmt.Errorf("'%s' yozuvini dekodlashda xatolik: %w", title, err)
+		}
+		entries[title] = entry
+	}
+	return entries, rows.Err()
+}
+
+// JSON ko'rinishida saqlangan ro'yxat ustunlarini yozuvga o'qish
+func decodeEntryLists(entry *Entry, roles, tags, videos string) error {
+	if err := json.Unmarshal([]byte(roles), &entry.Roles); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(tags), &entry.Tags); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(videos), &entry.Videos)
+}
+
+// Ro'yxatni JSON ustun uchun kodlash (nil bo'lsa bo'sh massiv)
+func encodeList[T any](values []T) (string, error) {
+	if values == nil {
+		values = []T{}
+	}
+	encoded, err := json.Marshal(values)
+	return string(encoded), err
+}
+
+// Bitta yozuvni o'qish
+func loadEntry(db sqlRunner, sectionID, title string) (Entry, bool, error) {
+	var entry Entry
+	var roles, tags, videos, createdAt, updatedAt string
+	err := db.QueryRow(`SELECT id, bio, roles, tags, videos, position, pinned, separate, created_at, updated_at
+		FROM entries WHERE section_id = ? AND title = ?`, sectionID, title).
+		Scan(&entry.ID, &entry.Bio, &roles, &tags, &videos, &entry.Position, &entry.Pinned, &entry.Separate, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("entries jadvalini o'qishda xatolik: %w", err)
+	}
+	entry.CreatedAt, entry.UpdatedAt = parseStoredTime(createdAt), parseStoredTime(updatedAt)
+	if err := decodeEntryLists(&entry, roles, tags, videos); err != nil {
+		return Entry{}, false, fmt.Errorf("'%s' yozuvini dekodlashda xatolik: %w", title, err)
+	}
+	return entry, true, nil
+}
+
+// Yozuvni saqlash
+func saveEntry(db sqlRunner, sectionID, title string, entry Entry) error {
+	roles, err := encodeList(entry.Roles)
+	if err != nil {
+		return fmt.Errorf("'%s' yozuvini kodlashda xatolik: %w", title, err)
+	}
+	tags, err := encodeList(entry.Tags)
+	if err != nil {
+		return fmt.Errorf("'%s' yozuvini kodlashda xatolik: %w", title, err)
+	}
+	videos, err := encodeList(entry.Videos)
+	if err != nil {
+		return fmt.Errorf("'%s' yozuvini kodlashda xatolik: %w", title, err)
+	}
+	_, err = db.Exec(`INSERT INTO entries (id, section_id, title, bio, roles, tags, videos, position, pinned, separate, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(section_id, title) DO UPDATE SET bio = excluded.bio, roles = excluded.roles,
+			tags = excluded.tags, videos = excluded.videos, position = excluded.position, pinned = excluded.pinned,
+			separate = excluded.separate, created_at = excluded.created_at, updated_at = excluded.updated_at`,
+		entry.ID, sectionID, title, entry.Bio, roles, tags, videos, entry.Position, entry.Pinned, entry.Separate,
+		entry.CreatedAt.Format(time.RFC3339Nano), entry.UpdatedAt.Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("entries jadvaliga yozishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Entry(sectionID, title string) (Entry, bool, error) {
+	return loadEntry(s.db, sectionID, title)
+}
+
+func (s *sqliteStore) SaveEntry(sectionID, title string, entry Entry) error {
+	return s.UpdateEntry(sectionID, title, func(existing *Entry, exists bool) error {
+		*existing = entry
+		return nil
+	})
+}
+
+// Yozuvni tranzaksiya ichida o'qish, o'zgartirish va saqlash
+func (s *sqliteStore) UpdateEntry(sectionID, title string, apply func(entry *Entry, exists bool) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("tranzaksiyani boshlashda xatolik: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, exists, err := loadSection(tx, sectionID); err != nil {
+		return err
+	} else if !exists {
+		return errNotFound
+	}
+	entry, exists, err := loadEntry(tx, sectionID, title)
+	if err != nil {
+		return err
+	}
+	previousID := entry.ID
+	if err := apply(&entry, exists); err != nil {
+		return err
+	}
+	if previousID != "" {
+		entry.ID = previousID
+	}
+	if entry.ID == "" {
+		id, err := newEntryID(func(id string) (bool, error) {
+			_, _, found, err := entryByID(tx, id)
+			return found, err
+		})
+		if err != nil {
+			return err
+		}
+		entry.ID = id
+	}
+	if err := saveEntry(tx, sectionID, title, entry); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tranzaksiyani yakunlashda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) FindEntry(id string) (string, string, bool, error) {
+	return entryByID(s.db, id)
+}
+
+// Yozuvni ID bo'yicha qidirish
+func entryByID(db sqlRunner, id string) (string, string, bool, error) {
+	var sectionID, title string
+	err := db.QueryRow(`SELECT section_id, title FROM entries WHERE id = ?`, id).Scan(&sectionID, &title)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, fmt.Errorf("entries jadvalini o'qishda xatolik: %w", err)
+	}
+	return sectionID, title, true, nil
+}
+
+// Yozuv nomini o'zgartirish: band nomni tekshirish va yangilash bitta tranzaksiyada
+func (s *sqliteStore) RenameEntry(sectionID, oldTitle, newTitle string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("tranzaksiyani boshlashda xatolik: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, taken, err := loadEntry(tx, sectionID, newTitle); err != nil {
+		return err
+	} else if taken {
+		return errEntryExists
+	}
+	result, err := tx.Exec(`UPDATE entries SET title = ? WHERE section_id = ? AND title = ?`, newTitle, sectionID, oldTitle)
+	if err != nil {
+		return fmt.Errorf("entries jadvaliga yozishda xatolik: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("entries jadvaliga yozishda xatolik: %w", err)
+	} else if affected == 0 {
+		return errNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tranzaksiyani yakunlashda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteEntry(sectionID, title string) error {
+	if _, err := s.db.Exec(`DELETE FROM entries WHERE section_id = ? AND title = ?`, sectionID, title); err != nil {
+		return fmt.Errorf("entries jadvalidan o'chirishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Admins() (map[string]AdminInfo, error) {
+	rows, err := s.db.Query(`SELECT username, added_by, added_at FROM admins`)
+	if err != nil {
+		return nil, fmt.Errorf("admins jadvalini o'qishda xatolik: %w", err)
+	}
+	defer rows.Close()
+
+	admins := make(map[string]AdminInfo)
+	for rows.Next() {
+		var info AdminInfo
+		var addedAt string
+		if err := rows.Scan(&info.Username, &info.AddedBy, &addedAt); err != nil {
+			return nil, fmt.Errorf("admins jadvalini o'qishda xatolik: %w", err)
+		}
+		info.AddedAt = parseStoredTime(addedAt)
+		admins[info.Username] = info
+	}
+	return admins, rows.Err()
+}
+
+func (s *sqliteStore) IsAdmin(username string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM admins WHERE username = ?)`, username).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("adminni tekshirishda xatolik: %w", err)
+	}
+	return exists, nil
+}
+
+func (s *sqliteStore) SaveAdmin(admin AdminInfo) error {
+	return saveAdmin(s.db, admin)
+}
+
+func saveAdmin(db sqlRunner, admin AdminInfo) error {
+	_, err := db.Exec(`INSERT INTO admins (username, added_by, added_at) VALUES (?, ?, ?)
+		ON CONFLICT(username) DO UPDATE SET added_by = excluded.added_by, added_at = excluded.added_at`,
+		admin.Username, admin.AddedBy, admin.AddedAt.Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("admins jadvaliga yozishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteAdmin(username string) error {
+	if _, err := s.db.Exec(`DELETE FROM admins WHERE username = ?`, username); err != nil {
+		return fmt.Errorf("admins jadvalidan o'chirishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) SaveUser(user UserInfo) error {
+	return saveUser(s.db, user)
+}
+
+func saveUser(db sqlRunner, user UserInfo) error {
+	_, err := db.Exec(`INSERT INTO users (id, first_name, last_name, username) VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET first_name = excluded.first_name, last_name = excluded.last_name, username = excluded.username`,
+		user.ID, user.FirstName, user.LastName, user.Username)
+	if err != nil {
+		return fmt.Errorf("users jadvaliga yozishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) User(id int64) (UserInfo, bool, error) {
+	user := UserInfo{ID: id}
+	err := s.db.QueryRow(`SELECT first_name, last_name, username FROM users WHERE id = ?`, id).
+		Scan(&user.FirstName, &user.LastName, &user.Username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return UserInfo{}, false, nil
+	}
+	if err != nil {
+		return UserInfo{}, false, fmt.Errorf("users jadvalini o'qishda xatolik: %w", err)
+	}
+	return user, true, nil
+}
+
+func (s *sqliteStore) Users() ([]UserInfo, error) {
+	rows, err := s.db.Query(`SELECT id, first_name, last_name, username FROM users ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("users jadvalini o'qishda xatolik: %w", err)
+	}
+	defer rows.Close()
+
+	var users []UserInfo
+	for rows.Next() {
+		var user UserInfo
+		if err := rows.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Username); err != nil {
+			return nil, fmt.Errorf("users jadvalini o'qishda xatolik: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (s *sqliteStore) SaveUserState(state UserState) error {
+	tempData, err := json.Marshal(state.TempData)
+	if err != nil {
+		return fmt.Errorf("foydalanuvchi holatini kodlashda xatolik: %w", err)
+	}
+	_, err = s.db.Exec(`INSERT INTO user_states (user_id, chat_id, state, temp_data, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET chat_id = excluded.chat_id, state = excluded.state,
+			temp_data = excluded.temp_data, updated_at = excluded.updated_at`,
+		state.UserID, state.ChatID, state.State, string(tempData), state.UpdatedAt.Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("user_states jadvaliga yozishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteUserState(userID int64) error {
+	if _, err := s.db.Exec(`DELETE FROM user_states WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("user_states jadvalidan o'chirishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) UserStates() ([]UserState, error) {
+	rows, err := s.db.Query(`SELECT user_id, chat_id, state, temp_data, updated_at FROM user_states`)
+	if err != nil {
+		return nil, fmt.Errorf("user_states jadvalini o'qishda xatolik: %w", err)
+	}
+	defer rows.Close()
+
+	var states []UserState
+	for rows.Next() {
+		var state UserState
+		var tempData, updatedAt string
+		if err := rows.Scan(&state.UserID, &state.ChatID, &state.State, &tempData, &updatedAt); err != nil {
+			return nil, fmt.Errorf("user_states jadvalini o'qishda xatolik: %w", err)
+		}
+		if err := json.Unmarshal([]byte(tempData), &state.TempData); err != nil {
+			return nil, fmt.Errorf("foydalanuvchi holatini dekodlashda xatolik: %w", err)
+		}
+		state.UpdatedAt = parseStoredTime(updatedAt)
+		states = append(states, state)
+	}
+	return states, rows.Err()
+}
+
+func (s *sqliteStore) AddAction(action UserAction) error {
+	_, err := s.db.Exec(`INSERT INTO actions (user_id, username, first_name, last_name, action, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		action.UserID, action.Username, action.FirstName, action.LastName,
+		action.Action, action.Details, action.Timestamp.Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("actions jadvaliga yozishda xatolik: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Actions() ([]UserAction, error) {
+	rows, err := s.db.Query(`SELECT user_id, username, first_name, last_name, action, details, created_at
+		FROM actions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("actions jadvalini o'qishda xatolik: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []UserAction
+	for rows.Next() {
+		var action UserAction
+		var createdAt string
+		if err := rows.Scan(&action.UserID, &action.Username, &action.FirstName, &action.LastName,
+			&action.Action, &action.Details, &createdAt); err != nil {
+			return nil, fmt.Errorf("actions jadvalini o'qishda xatolik: %w", err)
+		}
+		action.Timestamp = parseStoredTime(createdAt)
+		actions = append(actions, action)
+	}
+	return actions, rows.Err()
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
+
+// Bazada matn ko'rinishida saqlangan vaqtni o'qish
+func parseStoredTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Ikkala omborni ham yangi fayllar bilan ochish
+func openTestStores(t *testing.T) map[string]Store {
+	t.Helper()
+	dir := t.TempDir()
+	jsonStore, err := openJSONStore(filepath.Join(dir, "data.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqliteStore, err := openSQLiteStore(filepath.Join(dir, "bot.db"), filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		jsonStore.Close()
+		sqliteStore.Close()
+	})
+	return map[string]Store{"json": jsonStore, "sqlite": sqliteStore}
+}
+
+// Xatolikni solishtirish uchun nomga aylantirish
+func errName(err error) string {
+	for _, sentinel := range []struct {
+		err  error
+		name string
+	}{
+		{errNotFound, "errNotFound"},
+		{errEntryExists, "errEntryExists"},
+		{errRoleExists, "errRoleExists"},
+		{errLastRole, "errLastRole"},
+	} {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.name
+		}
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return "nil"
+}
+
+var testTime = time.Date(2025, 3, 7, 16, 20, 56, 0, time.UTC)
+
+func testEntry(id string, roles ...string) Entry {
+	return Entry{
+		ID:        id,
+		Bio:       "Zor geroy",
+		Roles:     roles,
+		Tags:      []string{"meta"},
+		Videos:    []Video{{ID: "4", Title: "Kirish", Duration: 90, FileID: "f4"}, {ID: "7"}},
+		Position:  2,
+		Pinned:    true,
+		Separate:  true,
+		CreatedAt: testTime,
+		UpdatedAt: testTime.Add(time.Hour),
+	}
+}
+
+// Ombor natijasi: ikkala omborda bir xil bo'lishi kerak
+type snapshot []any
+
+func TestStoreParity(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s Store) snapshot
+	}{
+		{
+			name: "standart bo'limlar va rollar",
+			run: func(t *testing.T, s Store) snapshot {
+				sections, err := s.Sections()
+				roles, err2 := s.Roles()
+				return snapshot{sections, errName(err), roles, errName(err2)}
+			},
+		},
+		{
+			name: "bo'lim saqlash va o'chirish yozuvlari bilan",
+			run: func(t *testing.T, s Store) snapshot {
+				err := s.SaveSection(Section{ID: "builds", Name: "Builds", Emoji: "🛠", Position: 3})
+				s.SaveEntry("builds", "Chichi", testEntry("aaaa0001", "Fighter"))
+				section, exists, _ := s.Section("builds")
+				errDelete := s.DeleteSection("builds")
+				_, existsAfter, _ := s.Section("builds")
+				entries, _ := s.Entries("builds")
+				_, _, found, _ := s.FindEntry("aaaa0001")
+				return snapshot{errName(err), section, exists, errName(errDelete), existsAfter, len(entries), found}
+			},
+		},
+		{
+			name: "yozuvni saqlash va o'qish",
+			run: func(t *testing.T, s Store) snapshot {
+				err := s.SaveEntry("tutorials", "Alucard / Laning", testEntry("aaaa0001", "Fighter", "Assassin"))
+				entry, exists, _ := s.Entry("tutorials", "Alucard / Laning")
+				entries, _ := s.Entries("tutorials")
+				sectionID, title, found, _ := s.FindEntry("aaaa0001")
+				_, _, missing, _ := s.FindEntry("ffffffff")
+				_, absent, _ := s.Entry("stories", "Alucard / Laning")
+				return snapshot{errName(err), entry, exists, entries, sectionID, title, found, missing, absent}
+			},
+		},
+		{
+			name: "UpdateEntry yangi yozuvga ID beradi va uni saqlaydi",
+			run: func(t *testing.T, s Store) snapshot {
+				err := s.UpdateEntry("tutorials", "Chichi", func(entry *Entry, exists bool) error {
+					entry.Roles = []string{"Tank"}
+					return nil
+				})
+				first, _, _ := s.Entry("tutorials", "Chichi")
+				err2 := s.UpdateEntry("tutorials", "Chichi", func(entry *Entry, exists bool) error {
+					entry.ID = "changed!"
+					entry.Bio = "yangi"
+					return nil
+				})
+				second, _, _ := s.Entry("tutorials", "Chichi")
+				if len(first.ID) != 8 || second.ID != first.ID {
+					t.Errorf("ID = %q, keyin %q", first.ID, second.ID)
+				}
+				return snapshot{errName(err), errName(err2), second.Bio, second.Roles}
+			},
+		},
+		{
+			name: "UpdateEntry xatolik qaytarsa hech narsa yozilmaydi",
+			run: func(t *testing.T, s Store) snapshot {
+				s.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+				err := s.UpdateEntry("tutorials", "Chichi", func(entry *Entry, exists bool) error {
+					entry.Roles = nil
+					return errLastRole
+				})
+				entry, _, _ := s.Entry("tutorials", "Chichi")
+				errMissing := s.UpdateEntry("nope", "Chichi", func(entry *Entry, exists bool) error { return nil })
+				return snapshot{errName(err), entry.Roles, errName(errMissing)}
+			},
+		},
+		{
+			name: "yozuv nomini o'zgartirish",
+			run: func(t *testing.T, s Store) snapshot {
+				s.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+				s.SaveEntry("tutorials", "Zed", testEntry("aaaa0002", "Assassin"))
+				taken := s.RenameEntry("tutorials", "Chichi", "Zed")
+				missing := s.RenameEntry("tutorials", "Nobody", "Someone")
+				err := s.RenameEntry("tutorials", "Chichi", "Fighters / Chichi")
+				entry, _, _ := s.Entry("tutorials", "Fighters / Chichi")
+				_, old, _ := s.Entry("tutorials", "Chichi")
+				_, title, _, _ := s.FindEntry("aaaa0001")
+				return snapshot{errName(taken), errName(missing), errName(err), entry, old, title}
+			},
+		},
+		{
+			name: "yozuvni o'chirish",
+			run: func(t *testing.T, s Store) snapshot {
+				s.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+				err := s.DeleteEntry("tutorials", "Chichi")
+				_, exists, _ := s.Entry("tutorials", "Chichi")
+				_, _, found, _ := s.FindEntry("aaaa0001")
+				return snapshot{errName(err), exists, found}
+			},
+		},
+		{
+			name: "rol nomini o'zgartirish yozuvlar bilan",
+			run: func(t *testing.T, s Store) snapshot {
+				s.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter", "Tank"))
+				s.SaveEntry("stories", "Diggi", testEntry("aaaa0002", "Support"))
+				err := s.RenameRole("Fighter", "Jangchi")
+				same := s.RenameRole("Tank", "Tank")
+				taken := s.RenameRole("Jangchi", "Mage")
+				missing := s.RenameRole("Nobody", "Someone")
+				roles, _ := s.Roles()
+				chichi, _, _ := s.Entry("tutorials", "Chichi")
+				diggi, _, _ := s.Entry("stories", "Diggi")
+				return snapshot{errName(err), errName(same), errName(taken), errName(missing), roles, chichi.Roles, diggi.Roles}
+			},
+		},
+		{
+			name: "rol saqlash va o'chirish",
+			run: func(t *testing.T, s Store) snapshot {
+				err := s.SaveRole(Role{Name: "Jungler", Position: 7, Hidden: true})
+				errDelete := s.DeleteRole("Mage")
+				roles, _ := s.Roles()
+				return snapshot{errName(err), errName(errDelete), roles}
+			},
+		},
+		{
+			name: "adminlar",
+			run: func(t *testing.T, s Store) snapshot {
+				err := s.SaveAdmin(AdminInfo{Username: "boss", AddedBy: "root", AddedAt: testTime})
+				s.SaveAdmin(AdminInfo{Username: "helper", AddedBy: "boss", AddedAt: testTime})
+				isAdmin, _ := s.IsAdmin("boss")
+				errDelete := s.DeleteAdmin("helper")
+				wasAdmin, _ := s.IsAdmin("helper")
+				admins, _ := s.Admins()
+				return snapshot{errName(err), isAdmin, errName(errDelete), wasAdmin, admins}
+			},
+		},
+		{
+			name: "foydalanuvchilar",
+			run: func(t *testing.T, s Store) snapshot {
+				err := s.SaveUser(UserInfo{ID: 2, FirstName: "Ali", Username: "ali"})
+				s.SaveUser(UserInfo{ID: 1, FirstName: "Vali"})
+				s.SaveUser(UserInfo{ID: 2, FirstName: "Ali", LastName: "Valiyev", Username: "ali"})
+				user, exists, _ := s.User(2)
+				_, missing, _ := s.User(3)
+				users, _ := s.Users()
+				return snapshot{errName(err), user, exists, missing, users}
+			},
+		},
+		{
+			name: "foydalanuvchi holatlari nusxa sifatida qaytariladi",
+			run: func(t *testing.T, s Store) snapshot {
+				state := UserState{UserID: 42, ChatID: 42, State: STATE_RENAME_ENTRY,
+					TempData: map[string]string{"updateSection": "tutorials", "updateTitle": "Chichi"}, UpdatedAt: testTime}
+				err := s.SaveUserState(state)
+				s.SaveUserState(UserState{UserID: 7, ChatID: 7, TempData: map[string]string{}, UpdatedAt: testTime})
+				state.TempData["updateTitle"] = "o'zgartirildi"
+
+				states, _ := s.UserStates()
+				for _, stored := range states {
+					stored.TempData["updateSection"] = "o'zgartirildi"
+				}
+				states, _ = s.UserStates()
+				errDelete := s.DeleteUserState(7)
+				remaining, _ := s.UserStates()
+				sort.Slice(states, func(i, j int) bool { return states[i].UserID < states[j].UserID })
+				return snapshot{errName(err), states, errName(errDelete), len(remaining)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores := openTestStores(t)
+			jsonResult := tt.run(t, stores["json"])
+			sqliteResult := tt.run(t, stores["sqlite"])
+			if !reflect.DeepEqual(jsonResult, sqliteResult) {
+				t.Errorf("omborlar farq qiladi:\n json:   %+v\n sqlite: %+v", jsonResult, sqliteResult)
+			}
+		})
+	}
+}
+
+// Omborlar bir xil bo'lishi yetarli emas - kutilgan natijalar ham tekshiriladi
+func TestStoreRenameRoleConflicts(t *testing.T) {
+	for name, s := range openTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			s.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+			if err := s.RenameRole("Fighter", "Tank"); !errors.Is(err, errRoleExists) {
+				t.Fatalf("RenameRole = %v, want errRoleExists", err)
+			}
+			entry, _, _ := s.Entry("tutorials", "Chichi")
+			if !reflect.DeepEqual(entry.Roles, []string{"Fighter"}) {
+				t.Errorf("Roles = %v, rad etilgan o'zgarish yozilmasligi kerak", entry.Roles)
+			}
+			roles, _ := s.Roles()
+			if len(roles) != len(defaultRoles()) {
+				t.Errorf("rollar soni %d, want %d", len(roles), len(defaultRoles()))
+			}
+		})
+	}
+}
+
+// JSON fayldan SQLite ga import: bir marta, bitta tranzaksiyada
+func TestSQLiteImportJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, s Store)
+		// import qilingandan keyin bazada qilinadigan o'zgarishlar
+		afterImport func(t *testing.T, s Store)
+		wantErr     bool
+		check       func(t *testing.T, s Store)
+	}{
+		{
+			name: "ma'lumotlar import qilinadi",
+			prepare: func(t *testing.T, s Store) {
+				s.SaveRole(Role{Name: "Jungler", Position: 9})
+				s.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Jungler"))
+				s.SaveAdmin(AdminInfo{Username: "boss", AddedAt: testTime})
+			},
+			check: func(t *testing.T, s Store) {
+				entry, exists, _ := s.Entry("tutorials", "Chichi")
+				if !exists || entry.ID != "aaaa0001" {
+					t.Errorf("yozuv import qilinmadi: %+v", entry)
+				}
+				if isAdmin, _ := s.IsAdmin("boss"); !isAdmin {
+					t.Error("admin import qilinmadi")
+				}
+			},
+		},
+		{
+			name: "yozuvsiz baza qayta ochilganda import takrorlanmaydi",
+			prepare: func(t *testing.T, s Store) {
+				s.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+			},
+			afterImport: func(t *testing.T, s Store) {
+				s.DeleteEntry("tutorials", "Chichi")
+				s.DeleteRole("Tank")
+				s.SaveRole(Role{Name: "Jungler", Position: 9})
+			},
+			check: func(t *testing.T, s Store) {
+				if _, exists, _ := s.Entry("tutorials", "Chichi"); exists {
+					t.Error("o'chirilgan yozuv qayta import qilindi")
+				}
+				roles, _ := s.Roles()
+				names := map[string]bool{}
+				for _, role := range roles {
+					names[role.Name] = true
+				}
+				if names["Tank"] || !names["Jungler"] {
+					t.Errorf("bazadagi rollar almashtirildi: %+v", roles)
+				}
+			},
+		},
+		{
+			name: "xatolikda import to'liq bekor qilinadi",
+			prepare: func(t *testing.T, s Store) {
+				s.SaveRole(Role{Name: "Jungler", Position: 9})
+				// Bir xil ID - ikkinchi yozuvda UNIQUE cheklovi buziladi
+				s.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+				s.SaveEntry("stories", "Chichi", testEntry("aaaa0001", "Fighter"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			jsonPath, dbPath := filepath.Join(dir, "data.json"), filepath.Join(dir, "bot.db")
+			source, err := openJSONStore(jsonPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.prepare(t, source)
+			source.Close()
+
+			s, err := openSQLiteStore(dbPath, jsonPath)
+			if tt.wantErr {
+				if err == nil {
+					s.Close()
+					t.Fatal("openSQLiteStore xatoliksiz ochildi")
+				}
+				// Yarim import saqlanmagan: faylsiz ochilganda rollar standart
+				s, err := openSQLiteStore(dbPath, filepath.Join(dir, "missing.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer s.Close()
+				roles, _ := s.Roles()
+				if !reflect.DeepEqual(roles, defaultRoles()) {
+					t.Errorf("rollar = %+v, want standart", roles)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.afterImport != nil {
+				tt.afterImport(t, s)
+			}
+			s.Close()
+
+			s, err = openSQLiteStore(dbPath, jsonPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			tt.check(t, s)
+		})
+	}
+}
+
+func TestNewEntryID(t *testing.T) {
+	seen := map[string]bool{"aaaa0001": true}
+	id, err := newEntryID(func(id string) (bool, error) { return seen[id], nil })
+	if err != nil || len(id) != 8 || seen[id] {
+		t.Errorf("newEntryID = (%q, %v)", id, err)
+	}
+
+	// Band ID lar cheksiz qidirilmaydi
+	attempts := 0
+	_, err = newEntryID(func(id string) (bool, error) {
+		attempts++
+		return true, nil
+	})
+	if err == nil || attempts != entryIDAttempts {
+		t.Errorf("hamma ID band: xatolik %v, %d ta urinish", err, attempts)
+	}
+
+	// Ombor xatoligi "band" deb hisoblanmaydi, o'zi qaytariladi
+	errLookup := errors.New("disk xatoligi")
+	attempts = 0
+	_, err = newEntryID(func(id string) (bool, error) {
+		attempts++
+		return false, errLookup
+	})
+	if !errors.Is(err, errLookup) || attempts != 1 {
+		t.Errorf("qidiruv xatoligi: %v, %d ta urinish", err, attempts)
+	}
+}
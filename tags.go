@@ -0,0 +1,239 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Bo'lim teglari ro'yxatini ochuvchi tugma
+const tagsButton = "🏷 Teglar"
+
+// Teg tugmalari shu belgi bilan boshlanadi
+const tagButtonPrefix = "#"
+
+// Tegni bir xil ko'rinishga keltirish: "#Patch 1.9" -> "patch-1.9"
+func normalizeTag(value string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(value))
+	tag = strings.TrimPrefix(tag, tagButtonPrefix)
+	tag = strings.Join(strings.Fields(tag), "-")
+	if tag == "" {
+		return "", errors.New("Teg bo'sh bo'lmasligi kerak.")
+	}
+	if strings.Contains(tag, ":") {
+		return "", errors.New("Tegda : belgisi bo'lmasligi kerak.")
+	}
+	// Teg callback ma'lumotiga (64 bayt) bo'lim ID si bilan birga sig'ishi kerak
+	if len(tag) > 20 {
+		return "", fmt.Errorf("'%s' tegi juda uzun (ko'pi bilan 20 ta lotin harfi).", tag)
+	}
+	return tag, nil
+}
+
+// Vergul bilan ajratilgan teglarni o'qish (takrorlanganlari olib tashlanadi)
+func parseTags(value string) ([]string, error) {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		tag, err := normalizeTag(part)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// Teglarni ko'rsatish uchun formatlash: "#beginner #jungle"
+func formatTags(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = tagButtonPrefix + tag
+	}
+	return strings.Join(parts, " ")
+}
+
+// Bo'limdagi barcha teglar (alifbo tartibida)
+func sectionTags(entries map[string]Entry) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, entry := range entries {
+		for _, tag := range entry.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Teglar usta qadami uchun tekshiruv (natija vergul bilan birlashtiriladi)
+func validateTags(value string) (string, error) {
+	tags, err := parseTags(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(tags, ", "), nil
+}
+
+// "🏷 Teglar" tugmasi
+func handleTagsButton(ctx *Context) {
+	section, exists, err := store.Section(ctx.State.TempData["section"])
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !exists {
+		sendMenu(ctx)
+		return
+	}
+	logUserAction(ctx.User, "Teglar ro'yxatini ochdi", section.Name)
+	showSectionTags(ctx, section)
+}
+
+// Bo'lim teglari ro'yxatini ko'rsatish
+func showSectionTags(ctx *Context, section Section) {
+	entries, err := store.Entries(section.ID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	tags := sectionTags(entries)
+	if len(tags) == 0 {
+		if inlineNavigation() {
+			showSection(ctx, section)
+			return
+		}
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("%s bo'limida hozircha teglar mavjud emas.", section.Button()))
+		return
+	}
+
+	ctx.State.TempData["section"] = section.ID
+	ctx.State.TempData["tags"] = "1"
+	delete(ctx.State.TempData, "selectedRole")
+	delete(ctx.State.TempData, "path")
+	delete(ctx.State.TempData, "tag")
+
+	text := fmt.Sprintf("%s: qaysi teg bo'yicha yozuvlarni ko'rmoqchisiz?", section.Button())
+	if inlineNavigation() {
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for i := 0; i < len(tags); i += 2 {
+			row := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(tagButtonPrefix+tags[i], sectionCallback("nav_tag:", section.ID, tags[i])))
+			if i+1 < len(tags) {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(tagButtonPrefix+tags[i+1], sectionCallback("nav_tag:", section.ID, tags[i+1])))
+			}
+			rows = append(rows, row)
+		}
+		rows = append(rows, inlineBackRow("s:"+section.ID))
+		sendOrEdit(ctx, editInPlace(ctx), text, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows})
+		return
+	}
+
+	var rows [][]tgbotapi.KeyboardButton
+	for i := 0; i < len(tags); i += 2 {
+		row := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(tagButtonPrefix + tags[i]))
+		if i+1 < len(tags) {
+			row = append(row, tgbotapi.NewKeyboardButton(tagButtonPrefix+tags[i+1]))
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("⬅️ Orqaga")))
+
+	keyboard := tgbotapi.NewReplyKeyboard(rows...)
+	keyboard.ResizeKeyboard = true
+
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = keyboard
+	outbox.Send(msg)
+}
+
+// Tegga ega yozuvlarni ko'rsatish
+func showTag(ctx *Context, section Section, tag string) {
+	setTagListing(ctx.State, section, tag)
+	showListing(ctx, 0, editInPlace(ctx))
+}
+
+// Tanlangan tegni saqlash (ro'yxat va orqaga qaytish uchun)
+func setTagListing(state *UserState, section Section, tag string) {
+	state.TempData["section"] = section.ID
+	state.TempData["tag"] = tag
+	delete(state.TempData, "selectedRole")
+	delete(state.TempData, "path")
+}
+
+// Teg tugmasi bosilganini aniqlash. Tanlov bajarilgan bo'lsa true qaytaradi.
+func selectTag(ctx *Context) bool {
+	if !strings.HasPrefix(ctx.Text, tagButtonPrefix) {
+		return false
+	}
+	section, exists, err := store.Section(ctx.State.TempData["section"])
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return true
+	}
+	if !exists {
+		return false
+	}
+	entries, err := store.Entries(section.ID)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return true
+	}
+	tag := strings.TrimPrefix(ctx.Text, tagButtonPrefix)
+	for _, existing := range sectionTags(entries) {
+		if existing == tag {
+			logUserAction(ctx.User, fmt.Sprintf("%s: '%s' tegini ko'rdi", section.Name, tag), "")
+			showTag(ctx, section, tag)
+			return true
+		}
+	}
+	return false
+}
+
+// Yozuv teglarini yangilashni boshlash
+func handleUpdateTagsCallback(ctx *Context) {
+	_, title, ok := beginEntryEdit(ctx, STATE_UPDATE_TAGS)
+	if !ok {
+		return
+	}
+	entry, _, err := store.Entry(ctx.State.TempData["updateSection"], title)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	current := "yo'q"
+	if len(entry.Tags) > 0 {
+		current = formatTags(entry.Tags)
+	}
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' teglari: %s\n\nYangi teglarni vergul bilan ajratib kiriting (masalan: beginner, jungle, patch-1.9). Barcha teglarni olib tashlash uchun \"-\" yuboring:", title, current))
+}
+
+// Yozuvning yangi teglari kiritildi
+func handleUpdateTagsInput(ctx *Context) {
+	var tags []string
+	if text := strings.TrimSpace(ctx.Text); text != "-" {
+		parsed, err := parseTags(text)
+		if err == nil && len(parsed) == 0 {
+			err = errors.New("Iltimos, kamida bitta teg kiriting yoki \"-\" yuboring.")
+		}
+		if err != nil {
+			sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+			return
+		}
+		tags = parsed
+	}
+	editEntry(ctx, "Admin: Yozuv teglari yangilandi", "'%s' teglari muvaffaqiyatli yangilandi!", func(entry *Entry) {
+		entry.Tags = tags
+	})
+}
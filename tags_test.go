@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		value, want string
+		valid       bool
+	}{
+		{"jungle", "jungle", true},
+		{"#Patch 1.9", "patch-1.9", true},
+		{"  Early   Game ", "early-game", true},
+		{"", "", false},
+		{"#", "", false},
+		{"a:b", "", false},
+		{strings.Repeat("a", 21), "", false},
+	}
+	for _, tt := range tests {
+		got, err := normalizeTag(tt.value)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("normalizeTag(%q) = (%q, %v), want %q (valid %v)", tt.value, got, err, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+		valid bool
+	}{
+		{"", nil, true},
+		{"jungle", []string{"jungle"}, true},
+		{"Jungle, #meta, jungle,, early game", []string{"jungle", "meta", "early-game"}, true},
+		{"meta, a:b", nil, false},
+	}
+	for _, tt := range tests {
+		got, err := parseTags(tt.value)
+		if (err == nil) != tt.valid || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTags(%q) = (%v, %v), want %v (valid %v)", tt.value, got, err, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestSectionTags(t *testing.T) {
+	entries := map[string]Entry{
+		"Chichi": {Tags: []string{"meta", "jungle"}},
+		"Zed":    {Tags: []string{"meta"}},
+		"Diggi":  {},
+	}
+	want := []string{"jungle", "meta"}
+	if got := sectionTags(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("sectionTags = %v, want %v", got, want)
+	}
+	if got := formatTags(want); got != "#jungle #meta" {
+		t.Errorf("formatTags = %q", got)
+	}
+}
+
+// Yozuv rollarini almashtirish: oxirgi rol olib tashlanmaydi va jurnalga yozilmaydi
+func TestUpdateEntryRoles(t *testing.T) {
+	tests := []struct {
+		name      string
+		roles     []string
+		input     string
+		wantRoles []string
+		wantText  string
+		wantLog   bool
+	}{
+		{"rol qo'shish", []string{"Fighter"}, "Tank", []string{"Fighter", "Tank"}, "qo'shildi", true},
+		{"rol olib tashlash", []string{"Fighter", "Tank"}, "✅ Fighter", []string{"Tank"}, "olib tashlandi", true},
+		{"oxirgi rol qoladi", []string{"Fighter"}, "✅ Fighter", []string{"Fighter"}, "kamida bitta rol", false},
+		{"noma'lum rol", []string{"Fighter"}, "Jungler", []string{"Fighter"}, "Noto'g'ri rol", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, "boss")
+			store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", tt.roles...))
+			state := getUserState(c.user.ID)
+			state.State = STATE_UPDATE_ROLE
+			state.TempData["updateSection"] = "tutorials"
+			state.TempData["updateTitle"] = "Chichi"
+
+			out := replies(c.send(tt.input))
+			entry, _, _ := store.Entry("tutorials", "Chichi")
+			if !reflect.DeepEqual(entry.Roles, tt.wantRoles) {
+				t.Errorf("rollar = %v, want %v", entry.Roles, tt.wantRoles)
+			}
+			if !strings.Contains(out, tt.wantText) {
+				t.Errorf("javob %q, want %q", out, tt.wantText)
+			}
+			actions, _ := store.Actions()
+			logged := false
+			for _, action := range actions {
+				if action.Action == "Admin: Yozuv rollari yangilandi" {
+					logged = true
+				}
+			}
+			if logged != tt.wantLog {
+				t.Errorf("jurnalga yozildi = %v, want %v", logged, tt.wantLog)
+			}
+		})
+	}
+}
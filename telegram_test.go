@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeTelegram - Bot API o'rnidagi test serveri. Har bir so'rovni yozib boradi
+// va muvaffaqiyatli javob qaytaradi (respond orqali javobni almashtirish mumkin).
+type fakeTelegram struct {
+	mu       sync.Mutex
+	requests []fakeRequest
+	lastID   int
+	// respond bo'sh bo'lmagan javob qaytarsa, standart javob o'rniga ishlatiladi
+	respond func(method string, form url.Values) string
+}
+
+type fakeRequest struct {
+	Method string
+	Form   url.Values
+	Files  []string // multipart bilan yuklangan fayl maydonlari
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/file/") {
+		// getFile orqali olingan faylni yuklab olish
+		fmt.Fprint(w, "fayl:"+r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		return
+	}
+	r.ParseMultipartForm(1 << 20)
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	f.mu.Lock()
+	f.lastID++
+	id := f.lastID
+	if method != "getMe" {
+		request := fakeRequest{Method: method, Form: r.Form}
+		if r.MultipartForm != nil {
+			for name := range r.MultipartForm.File {
+				request.Files = append(request.Files, name)
+			}
+			sort.Strings(request.Files)
+		}
+		f.requests = append(f.requests, request)
+	}
+	respond := f.respond
+	f.mu.Unlock()
+
+	if respond != nil {
+		if body := respond(method, r.Form); body != "" {
+			fmt.Fprint(w, body)
+			return
+		}
+	}
+	switch method {
+	case "getMe":
+		fmt.Fprint(w, `{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"bot","username":"test_bot"}}`)
+	case "copyMessage":
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d}}`, id)
+	case "sendMediaGroup":
+		fmt.Fprintf(w, `{"ok":true,"result":[{"message_id":%d,"chat":{"id":1},"date":0}]}`, id)
+	case "answerCallbackQuery", "deleteMessage":
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	case "getFile":
+		fmt.Fprintf(w, `{"ok":true,"result":{"file_id":%q,"file_size":10,"file_path":"files/%s"}}`, r.Form.Get("file_id"), r.Form.Get("file_id"))
+	default:
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d,"chat":{"id":1},"date":0}}`, id)
+	}
+}
+
+// Yozilgan so'rovlarni olib, ro'yxatni tozalash
+func (f *fakeTelegram) take() []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	requests := f.requests
+	f.requests = nil
+	return requests
+}
+
+// So'rov usullari ketma-ketligi (masalan, "sendMessage", "copyMessage")
+func methods(requests []fakeRequest) []string {
+	var names []string
+	for _, request := range requests {
+		names = append(names, request.Method)
+	}
+	return names
+}
+
+// Test uchun bot muhitini tayyorlash: sozlamalar, JSON ombor, soxta Telegram
+// va yuboruvchi. Global holatlar ham tozalanadi.
+func setupTestBot(t *testing.T) (*tgbotapi.BotAPI, *fakeTelegram) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg = defaultConfig()
+	cfg.AdminUsername = "boss"
+	cfg.PrivateChannel = "-1001234567890"
+	cfg.DataFile = filepath.Join(dir, "data.json")
+	cfg.SQLiteFile = filepath.Join(dir, "bot.db")
+	cfg.LogsDir = filepath.Join(dir, "logs")
+
+	var err error
+	store, err = openStore(cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	fake := &fakeTelegram{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outbox = newSender(bot, 1000, 1000, 0)
+	fileEndpoint = server.URL + "/file/bot%s/%s"
+	t.Cleanup(func() { fileEndpoint = tgbotapi.FileEndpoint })
+
+	probedVideos.Range(func(key, _ any) bool {
+		probedVideos.Delete(key)
+		return true
+	})
+
+	userStatesMu.Lock()
+	userStates = make(map[int64]*UserState)
+	userStatesMu.Unlock()
+	persistedMu.Lock()
+	persistedStates = make(map[int64]string)
+	persistedMu.Unlock()
+	return bot, fake
+}
+
+// testClient - botga bitta foydalanuvchi nomidan yangilanish yuboradi va
+// javob so'rovlarini qaytaradi (to'liq middleware zanjiri orqali)
+type testClient struct {
+	t       *testing.T
+	bot     *tgbotapi.BotAPI
+	fake    *fakeTelegram
+	handler updateHandler
+	user    *tgbotapi.User
+}
+
+func newTestClient(t *testing.T, username string) *testClient {
+	t.Helper()
+	bot, fake := setupTestBot(t)
+	routes := newBotRouter()
+	handler := chain(routes.Handle, recoverPanics, trackUserState, logActions, requireAdmin(routes))
+	return &testClient{t: t, bot: bot, fake: fake, handler: handler,
+		user: &tgbotapi.User{ID: 42, UserName: username, FirstName: username}}
+}
+
+// Matnli xabar yoki buyruq yuborish
+func (c *testClient) send(text string) []fakeRequest {
+	var update tgbotapi.Update
+	if strings.HasPrefix(text, "/") {
+		update = commandUpdate(c.user.ID, text)
+	} else {
+		update = messageUpdate(c.user.ID, text)
+	}
+	return c.sendMessage(update.Message)
+}
+
+// Tayyor xabarni yuborish (video, forward va h.k.)
+func (c *testClient) sendMessage(msg *tgbotapi.Message) []fakeRequest {
+	msg.From = c.user
+	msg.Chat = &tgbotapi.Chat{ID: c.user.ID}
+	c.fake.take()
+	c.handler(c.bot, tgbotapi.Update{Message: msg})
+	return c.fake.take()
+}
+
+// Inline tugmani bosish
+func (c *testClient) click(data string) []fakeRequest {
+	update := callbackUpdate(c.user.ID, data)
+	update.CallbackQuery.From = c.user
+	c.fake.take()
+	c.handler(c.bot, update)
+	return c.fake.take()
+}
+
+// So'rovlardagi matnlar va klaviaturalar (tekshirish uchun bitta satrda)
+func replies(requests []fakeRequest) string {
+	var parts []string
+	for _, request := range requests {
+		for _, key := range []string{"text", "caption", "media", "reply_markup"} {
+			if value := request.Form.Get(key); value != "" {
+				parts = append(parts, value)
+			}
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Har bir foydalanuvchi uchun oxirgi saqlangan holat (keraksiz yozishlarning oldini olish uchun)
+var (
+	persistedMu     sync.Mutex
+	persistedStates = make(map[int64]string)
+)
+
+// Jarayon davom ettirilganda foydalanuvchiga ko'rsatiladigan so'rovlar
+var stateResumePrompts = map[string]string{
+	STATE_UPDATE_BIO:      "'%s' uchun yangi bio matnini kiriting:",
+	STATE_ADD_VIDEO:       "'%s' uchun videoni yuboring, kanaldan forward qiling yoki havolasini yuboring:",
+	STATE_REPLACE_VIDEO:   "'%s' uchun yangi videoni yuboring, kanaldan forward qiling yoki havolasini yuboring:",
+	STATE_VIDEO_THUMBNAIL: "'%s' videosi uchun muqova rasmini yuboring:",
+	STATE_UPDATE_ROLE:     "'%s' rollarini belgilang:",
+	STATE_UPDATE_TAGS:     "'%s' uchun teglarni vergul bilan ajratib kiriting (olib tashlash uchun \"-\"):",
+	STATE_UPDATE_POSITION: "'%s' uchun yangi tartib raqamini kiriting (alifbo tartibi uchun 0):",
+	STATE_RENAME_ENTRY:    "'%s' uchun yangi nom kiriting:",
+	STATE_ADD_ADMIN:       "Yangi admin username'ini kiriting (@username ko'rinishida):",
+	STATE_RENAME_SECTION:  "'%s' bo'limi uchun yangi nom kiriting:",
+	STATE_SECTION_EMOJI:   "'%s' bo'limi uchun yangi emoji yuboring (olib tashlash uchun \"-\"):",
+	STATE_ADD_ROLE:        "Yangi rol nomini kiriting (masalan, Jungler):",
+	STATE_RENAME_ROLE:     "'%s' roli uchun yangi nom kiriting:",
+}
+
+// Holat davom ettirilishi kerak bo'lgan jarayonmi (menyu navigatsiyasi emas)
+func isResumableState(state *UserState) bool {
+	if _, exists := wizardFor(state.State); exists {
+		return true
+	}
+	_, exists := stateResumePrompts[state.State]
+	return exists
+}
+
+// Holat bo'yicha davom ettirish so'rovini tayyorlash
+func resumePrompt(state *UserState) string {
+	if w, exists := wizardFor(state.State); exists {
+		return w.prompt(state)
+	}
+
+	title := state.TempData["updateTitle"]
+	prompt := stateResumePrompts[state.State]
+	if title != "" {
+		prompt = fmt.Sprintf(prompt, title)
+	}
+	return prompt
+}
+
+// Foydalanuvchi holatini omborga saqlash yoki tugagan jarayonni o'chirish
+func persistUserState(userID, chatID int64) {
+	state, exists := findUserState(userID)
+	if !exists {
+		return
+	}
+
+	persistedMu.Lock()
+	defer persistedMu.Unlock()
+
+	if !isResumableState(state) {
+		if _, saved := persistedStates[userID]; saved {
+			if err := store.DeleteUserState(userID); err != nil {
+				log.Printf("Foydalanuvchi holatini o'chirishda xatolik: %v", err)
+				return
+			}
+			delete(persistedStates, userID)
+		}
+		return
+	}
+
+	snapshot, err := json.Marshal(struct {
+		State    string
+		TempData map[string]string
+	}{state.State, state.TempData})
+	if err != nil {
+		log.Printf("Foydalanuvchi holatini kodlashda xatolik: %v", err)
+		return
+	}
+	if persistedStates[userID] == string(snapshot) {
+		return
+	}
+	state.ChatID = chatID
+	state.UpdatedAt = time.Now()
+
+	if err := store.SaveUserState(*state); err != nil {
+		log.Printf("Foydalanuvchi holatini saqlashda xatolik: %v", err)
+		return
+	}
+	persistedStates[userID] = string(snapshot)
+}
+
+// Ishlayotgan bot ichida muddati o'tgan jarayonni bekor qilish
+func expireUserState(bot *tgbotapi.BotAPI, userID, chatID int64) {
+	state, exists := findUserState(userID)
+	if !exists || !isResumableState(state) || state.UpdatedAt.IsZero() {
+		return
+	}
+	if time.Since(state.UpdatedAt) <= cfg.UserStateTTL {
+		return
+	}
+
+	resetUserState(userID)
+	persistUserState(userID, chatID)
+	sendMessage(bot, chatID, "⌛ Oldingi jarayoningiz muddati o'tgani sababli bekor qilindi. Iltimos, qaytadan boshlang.")
+}
+
+// Bot ishga tushganda saqlangan holatlarni tiklash
+func restoreUserStates(bot *tgbotapi.BotAPI) {
+	states, err := store.UserStates()
+	if err != nil {
+		log.Printf("Foydalanuvchi holatlarini o'qishda xatolik: %v", err)
+		return
+	}
+
+	restored, expired := 0, 0
+	for _, saved := range states {
+		state := saved
+		if state.TempData == nil {
+			state.TempData = make(map[string]string)
+		}
+
+		// Muddati o'tgan yoki noma'lum holatlar tiklanmaydi
+		if time.Since(state.UpdatedAt) > cfg.UserStateTTL || !isResumableState(&state) {
+			if err := store.DeleteUserState(state.UserID); err != nil {
+				log.Printf("Foydalanuvchi holatini o'chirishda xatolik: %v", err)
+			}
+			sendMessage(bot, state.ChatID, "⌛ Bot qayta ishga tushdi va oldingi jarayoningiz muddati o'tib ketdi. Iltimos, qaytadan boshlang.")
+			expired++
+			continue
+		}
+
+		userStatesMu.Lock()
+		userStates[state.UserID] = &state
+		userStatesMu.Unlock()
+		persistedMu.Lock()
+		persistedStates[state.UserID] = ""
+		persistedMu.Unlock()
+
+		msg := tgbotapi.NewMessage(state.ChatID, "♻️ Bot qayta ishga tushdi, jarayoningiz saqlanib qoldi.\n\n"+resumePrompt(&state))
+		if w, exists := wizardFor(state.State); exists {
+			// Bot ishlamagan vaqt qadam vaqtiga qo'shilmaydi
+			w.touch(&state)
+			msg.ReplyMarkup = w.keyboard(&state)
+		}
+		if state.State == STATE_UPDATE_ROLE {
+			msg.ReplyMarkup = entryRoleKeyboard(state.TempData["updateSection"], state.TempData["updateTitle"])
+		}
+		outbox.Send(msg)
+		restored++
+	}
+
+	if restored > 0 || expired > 0 {
+		log.Printf("Foydalanuvchi holatlari: %d ta tiklandi, %d ta muddati o'tdi", restored, expired)
+	}
+}
+
+// Holatlarda yozuvga ishora qiluvchi kalitlar: bo'lim kaliti -> nom kaliti
+var entryStateKeys = [][2]string{
+	{"section", "selectedTitle"},
+	{"updateSection", "updateTitle"},
+}
+
+// entryRename - holatlarga hali qo'llanmagan yozuv nomi o'zgarishi
+type entryRename struct {
+	seq                        int
+	sectionID, oldTitle, title string
+	at                         time.Time
+}
+
+// Nomi o'zgargan yozuvlar. Boshqa foydalanuvchining holat obyektini faqat
+// uning ishchisi o'zgartiradi, shuning uchun o'zgarishlar navbatga yoziladi va
+// har bir foydalanuvchining navbatdagi yangilanishida qo'llanadi.
+var (
+	entryRenamesMu sync.Mutex
+	entryRenames   []entryRename
+	entryRenameSeq int
+	appliedRenames = make(map[int64]int) // foydalanuvchi -> oxirgi qo'llangan o'zgarish
+)
+
+// Yozuv nomi o'zgarganini barcha holatlarga yetkazish
+func renameEntryReferences(sectionID, oldTitle, newTitle string) {
+	entryRenamesMu.Lock()
+	entryRenameSeq++
+	entryRenames = append(entryRenames, entryRename{entryRenameSeq, sectionID, oldTitle, newTitle, time.Now()})
+
+	// Muddati o'tgan jarayonlar baribir bekor qilinadi - eski o'zgarishlar kerak emas
+	for len(entryRenames) > 0 && time.Since(entryRenames[0].at) > cfg.UserStateTTL {
+		entryRenames = entryRenames[1:]
+	}
+	// Navbatdagi eng eski o'zgarishni ham qo'llamagan foydalanuvchining yozuvi
+	// kerak emas: yozuv bo'lmasa ham navbatdagi hamma o'zgarish qo'llanadi
+	for userID, seq := range appliedRenames {
+		if len(entryRenames) == 0 || seq < entryRenames[0].seq {
+			delete(appliedRenames, userID)
+		}
+	}
+	entryRenamesMu.Unlock()
+
+	// Omborda saqlangan holatlar ham yangilanadi (bot qayta ishga tushsa).
+	// Holatlarni foydalanuvchi ishchilari persistUserState orqali persistedMu
+	// ostida yozadi. O'qish va yozish ham shu qulf ostida bajariladi, aks holda
+	// oraliqda saqlangan yangi holat eski nusxa bilan ustidan yozilib ketadi.
+	persistedMu.Lock()
+	defer persistedMu.Unlock()
+	states, err := store.UserStates()
+	if err != nil {
+		log.Printf("Foydalanuvchi holatlarini o'qishda xatolik: %v", err)
+		return
+	}
+	for _, state := range states {
+		if !renameInTempData(state.TempData, sectionID, oldTitle, newTitle) {
+			continue
+		}
+		if err := store.SaveUserState(state); err != nil {
+			log.Printf("Foydalanuvchi holatini saqlashda xatolik: %v", err)
+		}
+	}
+}
+
+// Foydalanuvchi holatiga hali qo'llanmagan nom o'zgarishlarini qo'llash.
+// Faqat shu foydalanuvchining ishchisidan chaqiriladi.
+func applyEntryRenames(userID int64) {
+	entryRenamesMu.Lock()
+	var pending []entryRename
+	for _, rename := range entryRenames {
+		if rename.seq > appliedRenames[userID] {
+			pending = append(pending, rename)
+		}
+	}
+	if len(entryRenames) > 0 {
+		appliedRenames[userID] = entryRenameSeq
+	}
+	entryRenamesMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	state, exists := findUserState(userID)
+	if !exists {
+		return
+	}
+	for _, rename := range pending {
+		renameInTempData(state.TempData, rename.sectionID, rename.oldTitle, rename.title)
+	}
+}
+
+// TempData dagi eski nomni yangisiga almashtirish. O'zgarish bo'lsa true.
+func renameInTempData(data map[string]string, sectionID, oldTitle, newTitle string) bool {
+	changed := false
+	for _, keys := range entryStateKeys {
+		if data[keys[0]] == sectionID && data[keys[1]] == oldTitle {
+			data[keys[1]] = newTitle
+			changed = true
+		}
+	}
+	return changed
+}
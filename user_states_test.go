@@ -0,0 +1,119 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestoreUserStates(t *testing.T) {
+	tests := []struct {
+		name        string
+		state       UserState
+		wantRestore bool
+		wantText    string
+	}{
+		{
+			name:        "yangi holat tiklanadi",
+			state:       UserState{State: STATE_UPDATE_BIO, TempData: map[string]string{"updateTitle": "Chichi"}},
+			wantRestore: true,
+			wantText:    "'Chichi' uchun yangi bio",
+		},
+		{
+			name:     "muddati o'tgan holat o'chiriladi",
+			state:    UserState{State: STATE_UPDATE_BIO, UpdatedAt: time.Now().Add(-25 * time.Hour)},
+			wantText: "muddati o'tib ketdi",
+		},
+		{
+			name:     "menyu holati tiklanmaydi",
+			state:    UserState{State: STATE_ENTRY_SELECTED},
+			wantText: "muddati o'tib ketdi",
+		},
+		{
+			name:        "bo'sh TempData bilan ham tiklanadi",
+			state:       UserState{State: STATE_ADD_ADMIN},
+			wantRestore: true,
+			wantText:    "Yangi admin username",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, fake := setupTestBot(t)
+			cfg.UserStateTTL = 24 * time.Hour
+			state := tt.state
+			state.UserID, state.ChatID = 42, 42
+			if state.UpdatedAt.IsZero() {
+				state.UpdatedAt = time.Now().Add(-time.Hour)
+			}
+			if err := store.SaveUserState(state); err != nil {
+				t.Fatal(err)
+			}
+
+			restoreUserStates(bot)
+
+			restored, exists := findUserState(42)
+			if exists != tt.wantRestore {
+				t.Fatalf("tiklandi = %v, want %v", exists, tt.wantRestore)
+			}
+			if exists && restored.TempData == nil {
+				t.Error("tiklangan holatda TempData nil")
+			}
+			stored, _ := store.UserStates()
+			if tt.wantRestore != (len(stored) == 1) {
+				t.Errorf("omborda %d ta holat qoldi", len(stored))
+			}
+			requests := fake.take()
+			if len(requests) != 1 || !strings.Contains(requests[0].Form.Get("text"), tt.wantText) {
+				t.Errorf("xabarlar = %+v, want %q", requests, tt.wantText)
+			}
+		})
+	}
+}
+
+// Yozuv nomi o'zgarganda omborda saqlangan holatlar yangilanadi, lekin shu
+// orada foydalanuvchi ishchisi saqlagan yangi holat yo'qolmaydi
+func TestRenameEntryReferences(t *testing.T) {
+	setupTestBot(t)
+	cfg.UserStateTTL = 24 * time.Hour
+	userStatesMu.Lock()
+	userStates[7] = &UserState{UserID: 7, ChatID: 7, State: STATE_UPDATE_BIO,
+		TempData: map[string]string{"updateSection": "tutorials", "updateTitle": "Chichi"}}
+	userStatesMu.Unlock()
+	persistUserState(7, 7)
+
+	// Foydalanuvchi ishchisi holatni saqlayotgan paytda nom o'zgaradi
+	persistedMu.Lock()
+	done := make(chan struct{})
+	go func() {
+		renameEntryReferences("tutorials", "Chichi", "Chichi / Jungle")
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("renameEntryReferences holat saqlanishini kutmadi")
+	case <-time.After(50 * time.Millisecond):
+	}
+	newer := UserState{UserID: 7, ChatID: 7, State: STATE_UPDATE_TAGS, UpdatedAt: time.Now(),
+		TempData: map[string]string{"updateSection": "tutorials", "updateTitle": "Chichi"}}
+	if err := store.SaveUserState(newer); err != nil {
+		t.Fatal(err)
+	}
+	persistedMu.Unlock()
+	<-done
+
+	states, err := store.UserStates()
+	if err != nil || len(states) != 1 {
+		t.Fatalf("UserStates = %v, %v", states, err)
+	}
+	if states[0].State != STATE_UPDATE_TAGS || states[0].TempData["updateTitle"] != "Chichi / Jungle" {
+		t.Errorf("saqlangan holat = %s %v", states[0].State, states[0].TempData)
+	}
+
+	// Xotiradagi holat foydalanuvchining navbatdagi yangilanishida yangilanadi
+	applyEntryRenames(7)
+	state, _ := findUserState(7)
+	if got := state.TempData["updateTitle"]; got != "Chichi / Jungle" {
+		t.Errorf("xotiradagi nom = %q", got)
+	}
+}
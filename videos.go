@@ -0,0 +1,901 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Video - yozuvdagi bitta video: private kanaldagi xabar ID si va
+// foydalanuvchiga caption sifatida ko'rsatiladigan ma'lumotlar.
+// Muqova (thumbnail) kanal postining o'zida bo'ladi: Bot API uni faqat video
+// fayl multipart bilan yuklanganda qabul qiladi, shuning uchun muqova
+// qo'yilganda video muqova bilan kanalga qayta joylanadi (repostVideoWithThumbnail).
+type Video struct {
+	ID          string `json:"id"` // private kanaldagi xabar raqami
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Duration    int    `json:"duration,omitempty"`  // soniyalarda (0 - noma'lum)
+	FileID      string `json:"file_id,omitempty"`   // Telegram fayl ID si (bot orqali yuklangan yoki forward qilingan bo'lsa)
+	Thumbnail   string `json:"thumbnail,omitempty"` // muqova rasmining fayl ID si (qo'yilgan bo'lsa)
+}
+
+// Telegram caption uzunligi chegarasi
+const captionLimit = 1024
+
+// Video yozuv ro'yxatidagi o'rni (-1 - yo'q)
+func videoIndex(videos []Video, videoID string) int {
+	for i, video := range videos {
+		if video.ID == videoID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Kamida bitta videoga nom berilganmi (aks holda ro'yxat faqat ID lardan iborat bo'lardi)
+func videosTitled(videos []Video) bool {
+	for _, video := range videos {
+		if video.Title != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Davomiylikni ko'rsatish: 754 -> "12:34", 3754 -> "1:02:34"
+func formatDuration(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// Davomiylikni o'qish: "754", "12:34" yoki "1:02:34"
+func parseDuration(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) > 3 {
+		return 0, errors.New("Davomiylik noto'g'ri. Masalan: 12:34 yoki 1:02:34")
+	}
+	seconds := 0
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 || (i > 0 && number >= 60) {
+			return 0, errors.New("Davomiylik noto'g'ri. Masalan: 12:34 yoki 1:02:34")
+		}
+		seconds = seconds*60 + number
+	}
+	if seconds == 0 {
+		return 0, errors.New("Davomiylik noldan katta bo'lishi kerak.")
+	}
+	return seconds, nil
+}
+
+// Ro'yxatlarda ko'rsatiladigan qisqa nom: "Laning asoslari (12:34)"
+func (v Video) Label() string {
+	label := v.Title
+	if label == "" {
+		label = "Video"
+	}
+	if v.Duration > 0 {
+		label += " (" + formatDuration(v.Duration) + ")"
+	}
+	return label
+}
+
+// Videoning o'z captioni: "🎬 2/3. Nom (12:34)" va tavsif. Yagona videoda
+// ma'lumot bo'lmasa bo'sh qaytadi.
+func videoCaption(video Video, index, total int) string {
+	heading := ""
+	if total > 1 {
+		heading = fmt.Sprintf("%d/%d", index+1, total)
+	}
+	if video.Title != "" {
+		if heading != "" {
+			heading += ". "
+		}
+		heading += video.Title
+	}
+	if video.Duration > 0 {
+		heading = strings.TrimSpace(heading + " (" + formatDuration(video.Duration) + ")")
+	}
+	caption := ""
+	if heading != "" {
+		caption = "🎬 " + heading
+	}
+	if video.Description != "" {
+		caption = strings.TrimSpace(caption + "\n" + video.Description)
+	}
+	return caption
+}
+
+// Captionni Telegram chegarasiga sig'dirish
+func fitCaption(caption string) string {
+	runes := []rune(caption)
+	if len(runes) <= captionLimit {
+		return caption
+	}
+	return string(runes[:captionLimit-1]) + "…"
+}
+
+// Videoni foydalanuvchiga yuborish. Usullar navbat bilan sinab ko'riladi:
+// saqlangan fayl ID si bilan sendVideo, kanaldan nusxalash, kanaldan forward.
+// Har bir muvaffaqiyatsizlik jurnalga yoziladi. Yuborilgan bo'lsa videoning
+// fayl ID si (aniqlab bo'lmasa "") va true qaytariladi.
+func deliverVideo(chatID int64, video Video, caption string) (string, bool) {
+	channelID := parseChannelID(cfg.PrivateChannel)
+	messageID := getMessageID(video.ID)
+
+	fileID := video.FileID
+	if fileID == "" {
+		fileID = learnFileID(video.ID)
+	}
+	if fileID != "" {
+		config := tgbotapi.NewVideo(chatID, tgbotapi.FileID(fileID))
+		config.Caption = caption
+		_, err := outbox.Send(config)
+		if err == nil {
+			return fileID, true
+		}
+		log.Printf("Video %s: fayl ID orqali yuborib bo'lmadi: %v", video.ID, err)
+	}
+
+	copyMsg := tgbotapi.NewCopyMessage(chatID, channelID, messageID)
+	copyMsg.Caption = caption
+	_, err := outbox.CopyMessage(copyMsg)
+	if err == nil {
+		// Nusxa fayl ID sini qaytarmaydi - u forward yoki botga yuklash orqali aniqlanadi
+		return "", true
+	}
+	log.Printf("Video %s: kanaldan nusxalab bo'lmadi: %v", video.ID, err)
+
+	forwarded, err := outbox.Send(tgbotapi.NewForward(chatID, channelID, messageID))
+	if err != nil {
+		log.Printf("Video %s: kanaldan forward qilib bo'lmadi: %v", video.ID, err)
+		return "", false
+	}
+	if forwarded.Video != nil {
+		return forwarded.Video.FileID, true
+	}
+	return "", true
+}
+
+// Fayl ID sini aniqlashga uringan videolar (bot ishlayotgan davr uchun):
+// postda video bo'lmasa, har bir yuborishda qayta forward qilinmaydi
+var probedVideos sync.Map
+
+// Kanal postidagi video fayl ID sini file_id_chat orqali aniqlash. Har bir
+// video uchun bir marta - natija yozuvga saqlanadi va keyingi yuborishlar
+// sendVideo bilan bo'ladi. Chat sozlanmagan bo'lsa, fayl ID si admin videoni
+// ko'rib chiqqanda aniqlanadi (handleVideoPreviewCallback).
+func learnFileID(videoID string) string {
+	if cfg.FileIDChat == "" {
+		return ""
+	}
+	if _, probed := probedVideos.LoadOrStore(videoID, true); probed {
+		return ""
+	}
+	return probeFileID(parseChannelID(cfg.FileIDChat), videoID)
+}
+
+// Kanal postini chatga forward qilib (javobda to'liq xabar keladi) fayl ID
+// sini olish. Forward darhol o'chiriladi. Kanalning o'ziga forward qilinmaydi -
+// obunachilarga keraksiz post va bildirishnoma bormasligi uchun.
+func probeFileID(chatID int64, videoID string) string {
+	probe, err := outbox.Send(tgbotapi.NewForward(chatID, parseChannelID(cfg.PrivateChannel), getMessageID(videoID)))
+	if err != nil {
+		log.Printf("Video %s: fayl ID sini aniqlab bo'lmadi: %v", videoID, err)
+		return ""
+	}
+	if _, err := outbox.Request(tgbotapi.NewDeleteMessage(chatID, probe.MessageID)); err != nil {
+		log.Printf("Video %s: vaqtinchalik forwardni o'chirib bo'lmadi: %v", videoID, err)
+	}
+	if probe.Video == nil {
+		return ""
+	}
+	return probe.Video.FileID
+}
+
+// Albomdagi elementlar soni chegarasi (Telegram sendMediaGroup)
+const mediaGroupLimit = 10
+
+// videos[start:end] ni albom bo'lib yuborish. Albom faqat saqlangan fayl ID
+// lari bilan tuziladi - chaqiruvchi faqat fayl ID si bor videolarni beradi.
+// Telegram rad etsa false qaytadi va chaqiruvchi videolarni alohida yuboradi.
+// heading - birinchi albom uchun yozuv ma'lumoti.
+func sendVideoAlbum(chatID int64, videos []Video, start, end int, heading string) bool {
+	chunk := videos[start:end]
+	media := make([]interface{}, 0, len(chunk))
+	for i, video := range chunk {
+		item := tgbotapi.NewInputMediaVideo(tgbotapi.FileID(video.FileID))
+		if i == 0 {
+			item.Caption = fitCaption(albumCaption(videos, start, end, heading))
+		}
+		media = append(media, item)
+	}
+
+	if _, err := outbox.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media)); err != nil {
+		log.Printf("Videolar %d-%d: albom bo'lib yuborib bo'lmadi: %v", start+1, end, err)
+		return false
+	}
+	return true
+}
+
+// Albom captioni: yozuv ma'lumoti, bo'laklar bo'lsa "🎬 11-20/23" va
+// nomlangan videolar ro'yxati
+func albumCaption(videos []Video, start, end int, heading string) string {
+	caption := strings.TrimSpace(heading)
+	if start > 0 || end < len(videos) {
+		caption += fmt.Sprintf("\n\n🎬 %d-%d/%d", start+1, end, len(videos))
+	}
+	if videosTitled(videos[start:end]) {
+		caption += "\n"
+		for i := start; i < end; i++ {
+			caption += fmt.Sprintf("\n%d. %s", i+1, videos[i].Label())
+		}
+	}
+	return strings.TrimSpace(caption)
+}
+
+// Yangi aniqlangan fayl ID larini yozuv videolariga saqlash (video ID -> fayl ID).
+// Bu kontent o'zgarishi emas, shuning uchun UpdatedAt o'zgartirilmaydi.
+func rememberFileIDs(sectionID, title string, fileIDs map[string]string) {
+	if len(fileIDs) == 0 {
+		return
+	}
+	err := store.UpdateEntry(sectionID, title, func(entry *Entry, exists bool) error {
+		if !exists {
+			return errNotFound
+		}
+		for i, video := range entry.Videos {
+			if fileID, learned := fileIDs[video.ID]; learned {
+				entry.Videos[i].FileID = fileID
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNotFound) {
+		log.Printf("Fayl ID larini saqlashda xatolik: %v", err)
+	}
+}
+
+// Video qo'shish usullari haqida so'rov matni
+const videoInputHint = "Videoni shu yerga yuboring (bot uni private kanalga joylaydi), kanaldan forward qiling, post havolasini (https://t.me/c/...) yuboring yoki xabar raqamini kiriting:"
+
+// Private kanal postiga havola: t.me/c/<kanal>/<xabar> yoki mavzuli
+// kanallarda t.me/c/<kanal>/<mavzu>/<xabar>
+var channelLinkPattern = regexp.MustCompile(`^(?:https?://)?(?:www\.)?t(?:elegram)?\.me/c/(\d+)/(?:\d+/)?(\d+)(?:[/?#].*)?$`)
+
+// Admin yuborgan xabardan videoni aniqlash: botga yuklangan video (kanalga
+// joylanadi), kanaldan forward, post havolasi yoki xabar raqami.
+// Boshqa chatdan forward qilingan xabarlar rad etiladi.
+func videoFromMessage(msg *tgbotapi.Message) (Video, error) {
+	if msg == nil {
+		return Video{}, errors.New(videoInputHint)
+	}
+	channelID := parseChannelID(cfg.PrivateChannel)
+
+	if msg.ForwardFromChat != nil {
+		if msg.ForwardFromChat.ID != channelID || msg.ForwardFromMessageID == 0 {
+			return Video{}, fmt.Errorf("Bu xabar boshqa chatdan (%s) forward qilingan. Videoni faqat private kanaldan forward qiling.", chatLabel(msg.ForwardFromChat))
+		}
+		video := Video{ID: strconv.Itoa(msg.ForwardFromMessageID)}
+		if msg.Video != nil {
+			video.Duration = msg.Video.Duration
+			video.FileID = msg.Video.FileID
+		}
+		return video, nil
+	}
+	if msg.ForwardFrom != nil || msg.ForwardSenderName != "" {
+		return Video{}, errors.New("Bu xabar foydalanuvchidan forward qilingan. Videoni faqat private kanaldan forward qiling.")
+	}
+	if msg.Video != nil {
+		return repostVideo(msg, channelID)
+	}
+
+	text := strings.TrimSpace(msg.Text)
+	if match := channelLinkPattern.FindStringSubmatch(text); match != nil {
+		if "-100"+match[1] != strconv.FormatInt(channelID, 10) {
+			return Video{}, errors.New("Havola boshqa kanalga tegishli. Faqat private kanal postlari havolasini yuboring.")
+		}
+		id, err := validateVideoID(match[2])
+		return Video{ID: id}, err
+	}
+	if text == "" {
+		return Video{}, errors.New(videoInputHint)
+	}
+	id, err := validateVideoID(text)
+	return Video{ID: id}, err
+}
+
+// Botga yuklangan videoni private kanalga joylash. Kanaldagi yangi xabar
+// raqami video ID si bo'ladi, fayl ID si esa keyingi yuborishlar uchun saqlanadi.
+func repostVideo(msg *tgbotapi.Message, channelID int64) (Video, error) {
+	posted, err := outbox.CopyMessage(tgbotapi.NewCopyMessage(channelID, msg.Chat.ID, msg.MessageID))
+	if err != nil {
+		log.Printf("Videoni kanalga joylashda xatolik: %v", err)
+		return Video{}, errors.New("Videoni private kanalga joylab bo'lmadi. Bot kanalda xabar yuborish huquqiga ega admin ekanini tekshiring.")
+	}
+	return Video{
+		ID:       strconv.Itoa(posted.MessageID),
+		Duration: msg.Video.Duration,
+		FileID:   msg.Video.FileID,
+	}, nil
+}
+
+// Muqova rasmiga Bot API talablari: JPEG, tomonlari 320 gacha, hajmi 200 KB gacha
+const (
+	thumbnailMaxSide = 320
+	thumbnailMaxSize = 200 * 1024
+)
+
+// Bot yuklab oladigan fayl hajmi chegarasi (getFile)
+const downloadLimit = 20 * 1024 * 1024
+
+// Telegram fayllarini yuklab olish manzili (testlarda almashtiriladi)
+var fileEndpoint = tgbotapi.FileEndpoint
+
+// Yuborilgan rasmdan muqovaga mos eng katta o'lcham. Telegram rasmni bir
+// nechta o'lchamda (JPEG) saqlaydi, kichiklari odatda 90 va 320 piksel.
+func thumbnailPhoto(photos []tgbotapi.PhotoSize) (string, bool) {
+	best := -1
+	for i, photo := range photos {
+		if photo.Width > thumbnailMaxSide || photo.Height > thumbnailMaxSide || photo.FileSize > thumbnailMaxSize {
+			continue
+		}
+		if best < 0 || photo.Width*photo.Height > photos[best].Width*photos[best].Height {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	return photos[best].FileID, true
+}
+
+// Telegram serveridagi faylni yuklab olish. Qayta urinishda multipart
+// so'rovni qayta o'qish mumkin bo'lishi uchun fayl xotiraga olinadi.
+func downloadFile(fileID, name string) (tgbotapi.FileBytes, error) {
+	resp, err := outbox.Request(tgbotapi.FileConfig{FileID: fileID})
+	if err != nil {
+		return tgbotapi.FileBytes{}, err
+	}
+	var file tgbotapi.File
+	if err := json.Unmarshal(resp.Result, &file); err != nil {
+		return tgbotapi.FileBytes{}, err
+	}
+	if file.FileSize > downloadLimit {
+		return tgbotapi.FileBytes{}, fmt.Errorf("fayl juda katta: %d bayt", file.FileSize)
+	}
+
+	download, err := http.Get(fmt.Sprintf(fileEndpoint, outbox.bot.Token, file.FilePath))
+	if err != nil {
+		return tgbotapi.FileBytes{}, err
+	}
+	defer download.Body.Close()
+	if download.StatusCode != http.StatusOK {
+		return tgbotapi.FileBytes{}, fmt.Errorf("faylni yuklab olib bo'lmadi: %s", download.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(download.Body, downloadLimit+1))
+	if err != nil {
+		return tgbotapi.FileBytes{}, err
+	}
+	if len(data) > downloadLimit {
+		return tgbotapi.FileBytes{}, fmt.Errorf("fayl juda katta: %d baytdan ortiq", downloadLimit)
+	}
+	return tgbotapi.FileBytes{Name: name, Bytes: data}, nil
+}
+
+// Videoni muqova bilan private kanalga qayta joylash: video va muqova bot
+// orqali yuklab olinib, birga multipart bilan yuklanadi. Kanaldagi eski post
+// o'zgarmaydi. Nom, tavsif va davomiylik saqlanadi, ID va fayl ID si yangi
+// postdan olinadi. Botlar 20 MB dan katta fayllarni yuklab ololmaydi.
+func repostVideoWithThumbnail(video Video, thumbnail string, channelID int64) (Video, error) {
+	file, err := downloadFile(video.FileID, "video.mp4")
+	if err != nil {
+		log.Printf("Video %s: faylni yuklab olishda xatolik: %v", video.ID, err)
+		return Video{}, errors.New("Video faylini yuklab bo'lmadi. Botlar 20 MB gacha bo'lgan fayllarni yuklab oladi - kattaroq videoga muqovani kanalga yuklashda qo'ying.")
+	}
+	thumb, err := downloadFile(thumbnail, "thumbnail.jpg")
+	if err != nil {
+		log.Printf("Muqova rasmini yuklab olishda xatolik: %v", err)
+		return Video{}, errors.New("Muqova rasmini yuklab bo'lmadi. Boshqa rasm yuboring.")
+	}
+
+	config := tgbotapi.NewVideo(channelID, file)
+	config.Thumb = thumb
+	config.Duration = video.Duration
+	config.SupportsStreaming = true
+	posted, err := outbox.Send(config)
+	if err != nil {
+		log.Printf("Video %s: muqova bilan kanalga joylashda xatolik: %v", video.ID, err)
+		return Video{}, errors.New("Videoni private kanalga joylab bo'lmadi. Bot kanalda xabar yuborish huquqiga ega admin ekanini tekshiring.")
+	}
+
+	video.ID = strconv.Itoa(posted.MessageID)
+	video.Thumbnail = thumbnail
+	video.FileID = ""
+	if posted.Video != nil {
+		video.FileID = posted.Video.FileID
+	}
+	return video, nil
+}
+
+// Yangi yozuv ustasining video qadami. Qiymat - video ID si, davomiylik va
+// fayl ID si esa TempData dagi "video:<ID>" kalitida saqlanadi.
+func wizardVideo(data map[string]string, msg *tgbotapi.Message) (string, error) {
+	video, err := videoFromMessage(msg)
+	if err != nil {
+		return "", err
+	}
+	for _, id := range splitList(data["video"]) {
+		if id == video.ID {
+			return "", errors.New("Bu video allaqachon qo'shilgan. Boshqa video yuboring yoki \"" + doneButton + "\" tugmasini bosing.")
+		}
+	}
+	if encoded, err := json.Marshal(video); err == nil {
+		data["video:"+video.ID] = string(encoded)
+	}
+	return video.ID, nil
+}
+
+// Ustada qo'shilgan videolar (kiritilgan tartibda)
+func wizardVideos(data map[string]string) []Video {
+	var videos []Video
+	for _, id := range splitList(data["video"]) {
+		video := Video{ID: id}
+		if encoded, exists := data["video:"+id]; exists {
+			if err := json.Unmarshal([]byte(encoded), &video); err != nil {
+				log.Printf("Video ma'lumotini o'qishda xatolik: %v", err)
+			}
+		}
+		videos = append(videos, video)
+	}
+	return videos
+}
+
+// Chat nomi xabarlar uchun: "@kanal" yoki sarlavha
+func chatLabel(chat *tgbotapi.Chat) string {
+	if chat.UserName != "" {
+		return "@" + chat.UserName
+	}
+	if chat.Title != "" {
+		return chat.Title
+	}
+	return strconv.FormatInt(chat.ID, 10)
+}
+
+// Video amali callbacki: "<amal>:<yozuv ID>:<video ID>". Tugmalar tartib
+// raqamini emas, video ID sini saqlaydi - ro'yxat o'zgargandan keyin
+// bosilgan eski tugma boshqa videoni o'chirib yubormasligi uchun.
+func callbackVideo(ctx *Context) (Section, string, string, bool) {
+	entryID, videoID, _ := strings.Cut(ctx.Arg, ":")
+	ctx.Arg = entryID
+	section, title, ok := callbackEntry(ctx)
+	return section, title, videoID, ok
+}
+
+// Admin uchun yozuv videolari ro'yxati (callback kelgan xabar joyida)
+func showEntryVideosForAdmin(ctx *Context, section Section, title string) {
+	entry, exists, err := store.Entry(section.ID, title)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	if !exists {
+		showSectionForAdmin(ctx, section, 0)
+		return
+	}
+
+	ref := entry.ID
+	var rows [][]tgbotapi.InlineKeyboardButton
+	text := fmt.Sprintf("%s %s\n\n🎬 Videolar: %d ta", section.Emoji, title, len(entry.Videos))
+	if len(entry.Videos) == 0 {
+		text += "\n\nBu yozuvda hali videolar mavjud emas."
+	} else {
+		text += "\n\n▶️ - ko'rish, ⬆️⬇️ - tartibni o'zgartirish, ✏️ - nom va tavsif, 🖼 - muqova, 🔁 - almashtirish, 🗑 - o'chirish"
+	}
+	for i, video := range entry.Videos {
+		text += fmt.Sprintf("\n%d. %s - ID %s", i+1, video.Label(), video.ID)
+		if video.Thumbnail != "" {
+			text += " 🖼"
+		}
+
+		arg := ref + ":" + video.ID
+		row := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("▶️ %d", i+1), "video_preview:"+arg))
+		if i > 0 {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData("⬆️", "video_up:"+arg))
+		}
+		if i < len(entry.Videos)-1 {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData("⬇️", "video_down:"+arg))
+		}
+		row = append(row,
+			tgbotapi.NewInlineKeyboardButtonData("✏️", "video_info:"+arg),
+			tgbotapi.NewInlineKeyboardButtonData("🖼", "video_thumb:"+arg),
+			tgbotapi.NewInlineKeyboardButtonData("🔁", "video_replace:"+arg),
+			tgbotapi.NewInlineKeyboardButtonData("🗑", "video_delete:"+arg),
+		)
+		rows = append(rows, row)
+	}
+	deliveryLabel := "📦 Yuborish: albom"
+	if entry.Separate {
+		deliveryLabel = "📨 Yuborish: alohida xabarlar"
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(deliveryLabel, "toggle_album:"+ref)),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("➕ Video qo'shish", "add_video:"+ref)),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("⬅️ Yozuvga qaytish", "manage_entry:"+ref)),
+	)
+	sendOrEdit(ctx, true, text, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows})
+}
+
+// "🎬 Videolar" tugmasi ("manage_videos:ID")
+func handleManageVideosCallback(ctx *Context) {
+	if section, title, ok := callbackEntry(ctx); ok {
+		showEntryVideosForAdmin(ctx, section, title)
+	}
+}
+
+// Bitta videoni adminga yuborish
+func handleVideoPreviewCallback(ctx *Context) {
+	section, title, videoID, ok := callbackVideo(ctx)
+	if !ok {
+		return
+	}
+	entry, _, err := store.Entry(section.ID, title)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	index := videoIndex(entry.Videos, videoID)
+	if index < 0 {
+		sendMessage(ctx.Bot, ctx.ChatID, "Video topilmadi.")
+		return
+	}
+
+	// Fayl ID si noma'lum bo'lsa, u adminning o'z chatiga forward orqali
+	// aniqlanadi va saqlanadi - foydalanuvchilarga video sendVideo bilan boradi
+	video := entry.Videos[index]
+	if video.FileID == "" {
+		video.FileID = probeFileID(ctx.ChatID, videoID)
+	}
+	caption := fitCaption(fmt.Sprintf("%s - %d-video (ID %s)\n\n%s", title, index+1, videoID, videoCaption(video, index, len(entry.Videos))))
+	fileID, ok := deliverVideo(ctx.ChatID, video, caption)
+	if !ok {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("Videoni yuborib bo'lmadi (ID %s). Ehtimol, kanaldagi xabar o'chirilgan - uni almashtiring yoki o'chiring.", videoID))
+		return
+	}
+	if fileID != "" && fileID != entry.Videos[index].FileID {
+		rememberFileIDs(section.ID, title, map[string]string{videoID: fileID})
+	}
+}
+
+// Yozuv videolarini o'zgartirish va ro'yxatni qayta ko'rsatish
+func changeEntryVideos(ctx *Context, action string, change func(entry *Entry, index int)) {
+	section, title, videoID, ok := callbackVideo(ctx)
+	if !ok {
+		return
+	}
+	err := store.UpdateEntry(section.ID, title, func(entry *Entry, exists bool) error {
+		if !exists {
+			return errNotFound
+		}
+		index := videoIndex(entry.Videos, videoID)
+		if index < 0 {
+			return errNotFound
+		}
+		change(entry, index)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errNotFound):
+		sendMessage(ctx.Bot, ctx.ChatID, "Video topilmadi.")
+	case err != nil:
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	default:
+		logUserAction(ctx.User, action, fmt.Sprintf("%s, video %s", entryLogRef(section.ID, title), videoID))
+	}
+	showEntryVideosForAdmin(ctx, section, title)
+}
+
+// Videolarni albom yoki alohida xabarlar bo'lib yuborishni almashtirish
+func handleToggleAlbumCallback(ctx *Context) {
+	section, title, ok := callbackEntry(ctx)
+	if !ok {
+		return
+	}
+	var separate bool
+	err := store.UpdateEntry(section.ID, title, func(entry *Entry, exists bool) error {
+		if !exists {
+			return errNotFound
+		}
+		entry.Separate = !entry.Separate
+		separate = entry.Separate
+		return nil
+	})
+	switch {
+	case errors.Is(err, errNotFound):
+		sendMessage(ctx.Bot, ctx.ChatID, "Yozuv topilmadi.")
+		return
+	case err != nil:
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+
+	action := "Admin: Videolar albom bo'lib yuboriladi"
+	if separate {
+		action = "Admin: Videolar alohida yuboriladi"
+	}
+	logUserAction(ctx.User, action, entryLogRef(section.ID, title))
+	showEntryVideosForAdmin(ctx, section, title)
+}
+
+// Videoni bir pog'ona yuqoriga surish
+func handleVideoUpCallback(ctx *Context) {
+	changeEntryVideos(ctx, "Admin: Video yuqoriga surildi", func(entry *Entry, index int) {
+		if index > 0 {
+			entry.Videos[index-1], entry.Videos[index] = entry.Videos[index], entry.Videos[index-1]
+		}
+	})
+}
+
+// Videoni bir pog'ona pastga surish
+func handleVideoDownCallback(ctx *Context) {
+	changeEntryVideos(ctx, "Admin: Video pastga surildi", func(entry *Entry, index int) {
+		if index < len(entry.Videos)-1 {
+			entry.Videos[index+1], entry.Videos[index] = entry.Videos[index], entry.Videos[index+1]
+		}
+	})
+}
+
+// Videoni yozuvdan o'chirish (kanaldagi xabar o'zgarmaydi)
+func handleVideoDeleteCallback(ctx *Context) {
+	changeEntryVideos(ctx, "Admin: Yozuvdan video o'chirildi", func(entry *Entry, index int) {
+		entry.Videos = append(entry.Videos[:index], entry.Videos[index+1:]...)
+	})
+}
+
+// Videoni almashtirishni boshlash
+func handleVideoReplaceCallback(ctx *Context) {
+	entryID, videoID, _ := strings.Cut(ctx.Arg, ":")
+	ctx.Arg = entryID
+	if _, title, ok := beginEntryEdit(ctx, STATE_REPLACE_VIDEO); ok {
+		ctx.State.TempData["updateVideo"] = videoID
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' yozuvidagi %s-ID li video o'rniga yangi video qo'shing. %s", title, videoID, videoInputHint))
+	}
+}
+
+// Almashtiriladigan videoning yangi ID si kiritildi
+func handleReplaceVideoInput(ctx *Context) {
+	video, err := videoFromMessage(ctx.Message)
+	if err != nil {
+		sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+		return
+	}
+	oldVideoID := ctx.State.TempData["updateVideo"]
+	if !checkDuplicateVideo(ctx, video.ID) {
+		return
+	}
+	editEntry(ctx, "Admin: Yozuvdagi video almashtirildi", "'%s' videosi muvaffaqiyatli almashtirildi!", func(entry *Entry) {
+		// Nom va tavsif yangi videoga o'tadi, davomiylik, fayl ID si va muqova esa yangi videodan olinadi
+		if index := videoIndex(entry.Videos, oldVideoID); index >= 0 {
+			entry.Videos[index].ID = video.ID
+			entry.Videos[index].Duration = video.Duration
+			entry.Videos[index].FileID = video.FileID
+			entry.Videos[index].Thumbnail = video.Thumbnail
+		} else {
+			// Eski video shu orada o'chirilgan - yangisi oxiriga qo'shiladi
+			entry.Videos = append(entry.Videos, video)
+		}
+		entry.UpdatedAt = time.Now()
+	})
+}
+
+// Video muqovasini qo'yishni boshlash. Muqova faqat fayli botga ma'lum
+// videoga qo'yiladi - video u bilan kanalga qayta yuklanadi.
+func handleVideoThumbnailCallback(ctx *Context) {
+	section, title, videoID, ok := callbackVideo(ctx)
+	if !ok {
+		return
+	}
+	entry, _, err := store.Entry(section.ID, title)
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	index := videoIndex(entry.Videos, videoID)
+	if index < 0 {
+		sendMessage(ctx.Bot, ctx.ChatID, "Video topilmadi.")
+		return
+	}
+	if entry.Videos[index].FileID == "" {
+		sendMessage(ctx.Bot, ctx.ChatID, "Bu videoning fayli botga noma'lum, shuning uchun unga muqova qo'yib bo'lmaydi. Avval videoni botga yuklab yoki kanaldan forward qilib almashtiring (🔁).")
+		return
+	}
+
+	ctx.State.State = STATE_VIDEO_THUMBNAIL
+	ctx.State.TempData["updateSection"] = section.ID
+	ctx.State.TempData["updateTitle"] = title
+	ctx.State.TempData["updateVideo"] = videoID
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' yozuvidagi %d-video uchun muqova rasmini yuboring. Video muqova bilan private kanalga qayta joylanadi.", title, index+1))
+}
+
+// Muqova rasmi yuborildi
+func handleVideoThumbnailInput(ctx *Context) {
+	thumbnail, ok := thumbnailPhoto(ctx.Message.Photo)
+	if !ok {
+		sendMessage(ctx.Bot, ctx.ChatID, "Iltimos, muqova uchun rasm yuboring (fayl emas, oddiy rasm sifatida) yoki /cancel bosing.")
+		return
+	}
+	videoID := ctx.State.TempData["updateVideo"]
+	entry, _, err := store.Entry(ctx.State.TempData["updateSection"], ctx.State.TempData["updateTitle"])
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return
+	}
+	index := videoIndex(entry.Videos, videoID)
+	if index < 0 || entry.Videos[index].FileID == "" {
+		sendMessage(ctx.Bot, ctx.ChatID, "Video topilmadi.")
+		sendAdminMenu(ctx.Bot, ctx.ChatID)
+		resetUserState(ctx.User.ID)
+		return
+	}
+
+	posted, err := repostVideoWithThumbnail(entry.Videos[index], thumbnail, parseChannelID(cfg.PrivateChannel))
+	if err != nil {
+		sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+		return
+	}
+	editEntry(ctx, "Admin: Videoga muqova qo'yildi", "'%s' videosiga muqova qo'yildi!", func(entry *Entry) {
+		if index := videoIndex(entry.Videos, videoID); index >= 0 {
+			entry.Videos[index].ID = posted.ID
+			entry.Videos[index].FileID = posted.FileID
+			entry.Videos[index].Thumbnail = posted.Thumbnail
+		}
+		entry.UpdatedAt = time.Now()
+	})
+}
+
+// Tahrirlanayotgan yozuvda bu video yo'qligini tekshirish. Bor bo'lsa adminga
+// aytiladi va holat saqlanadi - boshqa ID kiritish mumkin.
+func checkDuplicateVideo(ctx *Context, videoID string) bool {
+	entry, _, err := store.Entry(ctx.State.TempData["updateSection"], ctx.State.TempData["updateTitle"])
+	if err != nil {
+		reportStoreError(ctx.Bot, ctx.ChatID, err)
+		return false
+	}
+	if index := videoIndex(entry.Videos, videoID); index >= 0 {
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("Bu video yozuvda allaqachon bor (%d-video). Boshqa video yuboring yoki /cancel bosing.", index+1))
+		return false
+	}
+	return true
+}
+
+// Video nomi, tavsifi va davomiyligini kiritish ustasi. Tahrirlanayotgan video
+// updateSection, updateTitle va updateVideo kalitlarida saqlanadi.
+var videoInfoWizard = &wizard{
+	name:    "video_info",
+	heading: "🎬 Video ma'lumotlari:",
+	steps: []wizardStep{
+		{
+			key:      "video_title",
+			label:    "Nomi",
+			optional: true,
+			prompt: func(data map[string]string) string {
+				return videoInfoPrompt(data, "Video nomini kiriting", func(video Video) string { return video.Title })
+			},
+			validate: validateVideoText(100),
+		},
+		{
+			key:      "video_description",
+			label:    "Tavsif",
+			optional: true,
+			prompt: func(data map[string]string) string {
+				return videoInfoPrompt(data, "Video tavsifini kiriting", func(video Video) string { return video.Description })
+			},
+			validate: validateVideoText(700),
+		},
+		{
+			key:      "video_duration",
+			label:    "Davomiyligi",
+			optional: true,
+			prompt: func(data map[string]string) string {
+				return videoInfoPrompt(data, "Video davomiyligini kiriting (masalan, 12:34)", func(video Video) string {
+					if video.Duration == 0 {
+						return ""
+					}
+					return formatDuration(video.Duration)
+				})
+			},
+			validate: func(value string) (string, error) {
+				seconds, err := parseDuration(value)
+				if err != nil {
+					return "", err
+				}
+				return strconv.Itoa(seconds), nil
+			},
+			format: func(value string) string {
+				seconds, _ := strconv.Atoi(value)
+				return formatDuration(seconds)
+			},
+		},
+	},
+	finish: finishVideoInfo,
+}
+
+// Usta so'rovi videoning hozirgi qiymati bilan (o'tkazib yuborilsa qiymat tozalanadi)
+func videoInfoPrompt(data map[string]string, prompt string, current func(Video) string) string {
+	entry, _, err := store.Entry(data["updateSection"], data["updateTitle"])
+	if err != nil {
+		log.Printf("Yozuvni o'qishda xatolik: %v", err)
+	}
+	value := "yo'q"
+	if index := videoIndex(entry.Videos, data["updateVideo"]); index >= 0 && current(entry.Videos[index]) != "" {
+		value = current(entry.Videos[index])
+	}
+	return fmt.Sprintf("%s.\nHozirgi qiymat: %s\n\n\"%s\" - qiymatni olib tashlash.", prompt, value, wizardSkipButton)
+}
+
+// Matn uzunligini tekshiruvchi validator
+func validateVideoText(limit int) func(value string) (string, error) {
+	return func(value string) (string, error) {
+		if utf8.RuneCountInString(value) > limit {
+			return "", fmt.Errorf("Matn juda uzun (ko'pi bilan %d ta belgi).", limit)
+		}
+		return value, nil
+	}
+}
+
+// Video ma'lumotlarini tahrirlashni boshlash
+func handleVideoInfoCallback(ctx *Context) {
+	section, title, videoID, ok := callbackVideo(ctx)
+	if !ok {
+		return
+	}
+	ctx.State.TempData["updateSection"] = section.ID
+	ctx.State.TempData["updateTitle"] = title
+	ctx.State.TempData["updateVideo"] = videoID
+	videoInfoWizard.start(ctx)
+}
+
+// Kiritilgan video ma'lumotlarini saqlash
+func finishVideoInfo(ctx *Context, data map[string]string) error {
+	sectionID, title, videoID := data["updateSection"], data["updateTitle"], data["updateVideo"]
+	duration, _ := strconv.Atoi(data["video_duration"])
+	err := store.UpdateEntry(sectionID, title, func(entry *Entry, exists bool) error {
+		index := videoIndex(entry.Videos, videoID)
+		if !exists || index < 0 {
+			return errNotFound
+		}
+		entry.Videos[index].Title = data["video_title"]
+		entry.Videos[index].Description = data["video_description"]
+		entry.Videos[index].Duration = duration
+		entry.UpdatedAt = time.Now()
+		return nil
+	})
+	if errors.Is(err, errNotFound) {
+		sendMessage(ctx.Bot, ctx.ChatID, "Video topilmadi.")
+		sendAdminMenu(ctx.Bot, ctx.ChatID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	logUserAction(ctx.User, "Admin: Video ma'lumotlari yangilandi", fmt.Sprintf("%s, video %s", entryLogRef(sectionID, title), videoID))
+	sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("'%s' yozuvidagi video ma'lumotlari saqlandi!", title))
+	sendAdminMenu(ctx.Bot, ctx.ChatID)
+	return nil
+}
@@ -0,0 +1,552 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Yozuv videolari ID lari (tartib bilan)
+func entryVideoIDs(t *testing.T, sectionID, title string) []string {
+	t.Helper()
+	entry, _, err := store.Entry(sectionID, title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, video := range entry.Videos {
+		ids = append(ids, video.ID)
+	}
+	return ids
+}
+
+func TestVideoAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		click    string
+		wantIDs  []string
+		wantText string
+	}{
+		{"yuqoriga", "video_up:aaaa0001:7", []string{"7", "4", "9"}, "1. Video - ID 7"},
+		{"birinchisi joyida qoladi", "video_up:aaaa0001:4", []string{"4", "7", "9"}, "Videolar: 3 ta"},
+		{"pastga", "video_down:aaaa0001:7", []string{"4", "9", "7"}, "3. Video - ID 7"},
+		{"oxirgisi joyida qoladi", "video_down:aaaa0001:9", []string{"4", "7", "9"}, "Videolar: 3 ta"},
+		{"o'chirish", "video_delete:aaaa0001:4", []string{"7", "9"}, "Videolar: 2 ta"},
+		// Ro'yxat o'zgargandan keyin bosilgan eski tugma boshqa videoga tegmaydi
+		{"o'chirilgan video", "video_delete:aaaa0001:5", []string{"4", "7", "9"}, "Video topilmadi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, "boss")
+			entry := testEntry("aaaa0001", "Fighter")
+			entry.Videos = append(entry.Videos, Video{ID: "9"})
+			store.SaveEntry("tutorials", "Chichi", entry)
+
+			out := replies(c.click(tt.click))
+			if got := entryVideoIDs(t, "tutorials", "Chichi"); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("videolar = %v, want %v", got, tt.wantIDs)
+			}
+			if !strings.Contains(out, tt.wantText) {
+				t.Errorf("javobda %q yo'q:\n%s", tt.wantText, out)
+			}
+		})
+	}
+}
+
+func TestReplaceVideo(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantIDs  []string
+		wantText string
+	}{
+		{"yangi video", "15", []string{"15", "7"}, "muvaffaqiyatli almashtirildi"},
+		{"yozuvdagi video", "7", []string{"4", "7"}, "allaqachon bor (2-video)"},
+		{"noto'g'ri ID", "abc", []string{"4", "7"}, "musbat butun son"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, "boss")
+			store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+
+			c.click("video_replace:aaaa0001:4")
+			out := replies(c.send(tt.input))
+			if got := entryVideoIDs(t, "tutorials", "Chichi"); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("videolar = %v, want %v", got, tt.wantIDs)
+			}
+			if !strings.Contains(out, tt.wantText) {
+				t.Errorf("javobda %q yo'q:\n%s", tt.wantText, out)
+			}
+		})
+	}
+
+	// Nom va tavsif yangi videoga o'tadi, eski fayl ID si esa qolmaydi
+	c := newTestClient(t, "boss")
+	store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+	c.click("video_replace:aaaa0001:4")
+	c.send("15")
+	entry, _, _ := store.Entry("tutorials", "Chichi")
+	if got := entry.Videos[0]; got.Title != "Kirish" || got.FileID != "" || got.Duration != 0 {
+		t.Errorf("almashtirilgan video = %+v", got)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[int]string{0: "0:00", 59: "0:59", 754: "12:34", 3600: "1:00:00", 3754: "1:02:34"}
+	for seconds, want := range tests {
+		if got := formatDuration(seconds); got != want {
+			t.Errorf("formatDuration(%d) = %q, want %q", seconds, got, want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+		valid bool
+	}{
+		{"754", 754, true},
+		{"12:34", 754, true},
+		{" 1:02:34 ", 3754, true},
+		{"0:05", 5, true},
+		{"0", 0, false},
+		{"0:00", 0, false},
+		{"12:60", 0, false},
+		{"1:2:3:4", 0, false},
+		{"-5", 0, false},
+		{"12:ab", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseDuration(tt.value)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("parseDuration(%q) = (%d, %v), want %d (valid %v)", tt.value, got, err, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestThumbnailPhoto(t *testing.T) {
+	tests := []struct {
+		name   string
+		photos []tgbotapi.PhotoSize
+		want   string
+	}{
+		{"rasm yo'q", nil, ""},
+		{
+			name: "320 gacha eng kattasi",
+			photos: []tgbotapi.PhotoSize{
+				{FileID: "s", Width: 90, Height: 60, FileSize: 1000},
+				{FileID: "m", Width: 320, Height: 213, FileSize: 15000},
+				{FileID: "x", Width: 800, Height: 533, FileSize: 70000},
+			},
+			want: "m",
+		},
+		{
+			name: "hajmi katta",
+			photos: []tgbotapi.PhotoSize{
+				{FileID: "s", Width: 90, Height: 90, FileSize: 1000},
+				{FileID: "m", Width: 320, Height: 320, FileSize: 300 * 1024},
+			},
+			want: "s",
+		},
+		{"faqat katta rasm", []tgbotapi.PhotoSize{{FileID: "x", Width: 800, Height: 800}}, ""},
+	}
+	for _, tt := range tests {
+		got, ok := thumbnailPhoto(tt.photos)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("%s: thumbnailPhoto = (%q, %v), want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+// Muqova qo'yilganda video va rasm yuklab olinib, kanalga birga yuklanadi
+func TestVideoThumbnail(t *testing.T) {
+	c := newTestClient(t, "boss")
+	c.fake.respond = func(method string, form url.Values) string {
+		if method == "sendVideo" {
+			return `{"ok":true,"result":{"message_id":77,"chat":{"id":-1001234567890},"date":0,"video":{"file_id":"covered","duration":90}}}`
+		}
+		return ""
+	}
+	store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+
+	// Fayli noma'lum videoga muqova qo'yilmaydi
+	if out := replies(c.click("video_thumb:aaaa0001:7")); !strings.Contains(out, "fayli botga noma'lum") {
+		t.Errorf("fayl ID siz video: %s", out)
+	}
+
+	c.click("video_thumb:aaaa0001:4")
+	if out := replies(c.send("rasm emas")); !strings.Contains(out, "rasm yuboring") {
+		t.Errorf("matn yuborilganda: %s", out)
+	}
+
+	requests := c.sendMessage(&tgbotapi.Message{MessageID: 5, Photo: []tgbotapi.PhotoSize{
+		{FileID: "thumb-small", Width: 90, Height: 90, FileSize: 2000},
+		{FileID: "thumb-big", Width: 1280, Height: 1280, FileSize: 200000},
+	}})
+	var upload *fakeRequest
+	for i, request := range requests {
+		if request.Method == "sendVideo" {
+			upload = &requests[i]
+		}
+	}
+	if upload == nil {
+		t.Fatalf("sendVideo yuborilmadi: %v", methods(requests))
+	}
+	if got := upload.Form.Get("chat_id"); got != cfg.PrivateChannel {
+		t.Errorf("video %s ga yuklandi, want kanal", got)
+	}
+	if want := []string{"thumb", "video"}; !reflect.DeepEqual(upload.Files, want) {
+		t.Errorf("yuklangan fayllar = %v, want %v", upload.Files, want)
+	}
+
+	entry, _, _ := store.Entry("tutorials", "Chichi")
+	want := Video{ID: "77", Title: "Kirish", Duration: 90, FileID: "covered", Thumbnail: "thumb-small"}
+	if entry.Videos[0] != want {
+		t.Errorf("video = %+v, want %+v", entry.Videos[0], want)
+	}
+	if state, _ := findUserState(c.user.ID); state.State != STATE_NONE {
+		t.Errorf("holat = %q", state.State)
+	}
+}
+
+func TestChannelLinkPattern(t *testing.T) {
+	tests := []struct {
+		link             string
+		channel, message string
+		match            bool
+	}{
+		{"https://t.me/c/1234567890/42", "1234567890", "42", true},
+		{"t.me/c/1234567890/42", "1234567890", "42", true},
+		{"https://telegram.me/c/1234567890/42", "1234567890", "42", true},
+		{"https://t.me/c/1234567890/42/", "1234567890", "42", true},
+		{"https://t.me/c/1234567890/42?single", "1234567890", "42", true},
+		{"https://t.me/c/1234567890/5/42", "1234567890", "42", true}, // mavzuli kanal
+		{"https://t.me/channel/42", "", "", false},
+		{"https://t.me/c/1234567890", "", "", false},
+		{"https://example.com/c/1234567890/42", "", "", false},
+	}
+	for _, tt := range tests {
+		match := channelLinkPattern.FindStringSubmatch(tt.link)
+		if (match != nil) != tt.match {
+			t.Errorf("%q: mos keldi = %v, want %v", tt.link, match != nil, tt.match)
+			continue
+		}
+		if match != nil && (match[1] != tt.channel || match[2] != tt.message) {
+			t.Errorf("%q: kanal %q, xabar %q, want %q, %q", tt.link, match[1], match[2], tt.channel, tt.message)
+		}
+	}
+}
+
+func TestVideoFromMessage(t *testing.T) {
+	setupTestBot(t)
+	channel := &tgbotapi.Chat{ID: -1001234567890, Title: "Kanal"}
+	tests := []struct {
+		name    string
+		msg     *tgbotapi.Message
+		want    Video
+		wantErr string
+	}{
+		{"xabar raqami", &tgbotapi.Message{Text: "42"}, Video{ID: "42"}, ""},
+		{"havola", &tgbotapi.Message{Text: "https://t.me/c/1234567890/42"}, Video{ID: "42"}, ""},
+		{"mavzu havolasi", &tgbotapi.Message{Text: "https://t.me/c/1234567890/3/42/"}, Video{ID: "42"}, ""},
+		{"boshqa kanal havolasi", &tgbotapi.Message{Text: "https://t.me/c/999/42"}, Video{}, "boshqa kanalga"},
+		{
+			name: "kanaldan forward",
+			msg: &tgbotapi.Message{ForwardFromChat: channel, ForwardFromMessageID: 42,
+				Video: &tgbotapi.Video{FileID: "f42", Duration: 90}},
+			want: Video{ID: "42", Duration: 90, FileID: "f42"},
+		},
+		{
+			name:    "boshqa kanaldan forward",
+			msg:     &tgbotapi.Message{ForwardFromChat: &tgbotapi.Chat{ID: -100999, UserName: "begona"}, ForwardFromMessageID: 42},
+			wantErr: "@begona",
+		},
+		{"foydalanuvchidan forward", &tgbotapi.Message{ForwardSenderName: "Ali", Text: "42"}, Video{}, "foydalanuvchidan"},
+		{"noto'g'ri matn", &tgbotapi.Message{Text: "video"}, Video{}, "musbat butun son"},
+		{"bo'sh xabar", &tgbotapi.Message{}, Video{}, "Videoni shu yerga"},
+	}
+	for _, tt := range tests {
+		got, err := videoFromMessage(tt.msg)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: xatolik = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: videoFromMessage = (%+v, %v), want %+v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+// Botga yuklangan video private kanalga nusxalanadi va kanal posti yozuvga qo'shiladi
+func TestUploadVideo(t *testing.T) {
+	c := newTestClient(t, "boss")
+	store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+	c.fake.respond = func(method string, form url.Values) string {
+		if method == "copyMessage" {
+			return `{"ok":true,"result":{"message_id":900}}`
+		}
+		return ""
+	}
+	upload := &tgbotapi.Message{MessageID: 500, Video: &tgbotapi.Video{FileID: "uploaded", Duration: 61}}
+
+	c.click("add_video:aaaa0001")
+	requests := c.sendMessage(upload)
+	if len(requests) == 0 || requests[0].Method != "copyMessage" {
+		t.Fatalf("so'rovlar = %v, want copyMessage birinchi", methods(requests))
+	}
+	copied := requests[0].Form
+	if copied.Get("chat_id") != cfg.PrivateChannel || copied.Get("from_chat_id") != "42" || copied.Get("message_id") != "500" {
+		t.Errorf("copyMessage = %v", copied)
+	}
+	entry, _, _ := store.Entry("tutorials", "Chichi")
+	posted := entry.Videos[len(entry.Videos)-1]
+	if want := (Video{ID: "900", Duration: 61, FileID: "uploaded"}); posted != want {
+		t.Errorf("qo'shilgan video = %+v, want %+v", posted, want)
+	}
+
+	// Kanalga joylab bo'lmasa video qo'shilmaydi va holat saqlanadi
+	c.fake.respond = func(method string, form url.Values) string {
+		if method == "copyMessage" {
+			return `{"ok":false,"error_code":403,"description":"Forbidden: bot is not a member of the channel chat"}`
+		}
+		return ""
+	}
+	out := replies(c.sendMessage(&tgbotapi.Message{MessageID: 501, Video: &tgbotapi.Video{FileID: "second"}}))
+	if !strings.Contains(out, "kanalga joylab bo'lmadi") {
+		t.Errorf("javob = %q", out)
+	}
+	if got := entryVideoIDs(t, "tutorials", "Chichi"); len(got) != 3 {
+		t.Errorf("videolar = %v, want 3 ta", got)
+	}
+	if state, _ := findUserState(c.user.ID); state.State != STATE_ADD_VIDEO {
+		t.Errorf("holat = %q", state.State)
+	}
+}
+
+// Ustada yuklangan videoning davomiyligi va fayl ID si yozuvga yetib boradi
+func TestWizardVideos(t *testing.T) {
+	setupTestBot(t)
+	data := map[string]string{}
+	for _, msg := range []*tgbotapi.Message{
+		{Text: "42"},
+		{ForwardFromChat: &tgbotapi.Chat{ID: -1001234567890}, ForwardFromMessageID: 43, Video: &tgbotapi.Video{FileID: "f43", Duration: 30}},
+	} {
+		id, err := wizardVideo(data, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data["video"] = strings.TrimPrefix(data["video"]+","+id, ",")
+	}
+	if _, err := wizardVideo(data, &tgbotapi.Message{Text: "43"}); err == nil {
+		t.Error("takroriy video qabul qilindi")
+	}
+
+	want := []Video{{ID: "42"}, {ID: "43", Duration: 30, FileID: "f43"}}
+	if got := wizardVideos(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("wizardVideos = %+v, want %+v", got, want)
+	}
+}
+
+// Kanal postini forward qilganda javobda video fayl ID si keladi
+func respondForwardedVideo(method string, form url.Values) string {
+	if method == "forwardMessage" {
+		return `{"ok":true,"result":{"message_id":800,"chat":{"id":1},"date":0,"video":{"file_id":"learned` + form.Get("message_id") + `"}}}`
+	}
+	return ""
+}
+
+// Fayl ID si noma'lum video bir marta aniqlanadi: keyingi yuborishlar sendVideo bilan
+func TestDeliverVideoFileID(t *testing.T) {
+	t.Run("file_id_chat orqali", func(t *testing.T) {
+		c := newTestClient(t, "guest")
+		cfg.FileIDChat = "555"
+		c.fake.respond = respondForwardedVideo
+		store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+
+		requests := c.click("entry_videos:aaaa0001")
+		var probed bool
+		for _, request := range requests {
+			if request.Method == "forwardMessage" && request.Form.Get("chat_id") != "555" {
+				t.Errorf("forwardMessage chat_id = %s, want 555", request.Form.Get("chat_id"))
+			}
+			if request.Method == "deleteMessage" && request.Form.Get("chat_id") == "555" && request.Form.Get("message_id") == "800" {
+				probed = true
+			}
+		}
+		if !probed {
+			t.Errorf("vaqtinchalik forward o'chirilmadi: %v", methods(requests))
+		}
+		entry, _, _ := store.Entry("tutorials", "Chichi")
+		if entry.Videos[1].FileID != "learned7" {
+			t.Errorf("saqlangan fayl ID = %q, want learned7", entry.Videos[1].FileID)
+		}
+
+		got := methods(c.click("entry_videos:aaaa0001"))
+		if want := []string{"answerCallbackQuery", "sendVideo", "sendVideo", "sendMessage"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ikkinchi yuborish = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("admin ko'rib chiqqanda", func(t *testing.T) {
+		c := newTestClient(t, "boss")
+		c.fake.respond = respondForwardedVideo
+		store.SaveEntry("tutorials", "Chichi", testEntry("aaaa0001", "Fighter"))
+
+		// Chat sozlanmagan: kanalga hech narsa forward qilinmaydi, nusxa yuboriladi
+		got := methods(c.click("entry_videos:aaaa0001"))
+		if want := []string{"answerCallbackQuery", "sendVideo", "copyMessage", "sendMessage"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("birinchi yuborish = %v, want %v", got, want)
+		}
+
+		requests := c.click("video_preview:aaaa0001:7")
+		if got, want := methods(requests), []string{"answerCallbackQuery", "forwardMessage", "deleteMessage", "sendVideo"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("ko'rib chiqish = %v, want %v", got, want)
+		}
+		if chat := requests[1].Form.Get("chat_id"); chat != "42" {
+			t.Errorf("forwardMessage chat_id = %s, want adminning chati", chat)
+		}
+
+		got = methods(c.click("entry_videos:aaaa0001"))
+		if want := []string{"answerCallbackQuery", "sendVideo", "sendVideo", "sendMessage"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ikkinchi yuborish = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestFitCaption(t *testing.T) {
+	if got := fitCaption("Qisqa"); got != "Qisqa" {
+		t.Errorf("fitCaption = %q", got)
+	}
+	exact := strings.Repeat("a", captionLimit)
+	if got := fitCaption(exact); got != exact {
+		t.Errorf("chegaradagi caption qisqartirildi: %d belgi", len([]rune(got)))
+	}
+	// Chegara baytlarda emas, belgilarda: ko'p baytli harflar kesilmaydi
+	got := []rune(fitCaption(strings.Repeat("ў", captionLimit+5)))
+	if len(got) != captionLimit || got[len(got)-1] != '…' || got[0] != 'ў' {
+		t.Errorf("uzun caption: %d belgi, oxiri %q", len(got), string(got[len(got)-1]))
+	}
+}
+
+func TestAlbumCaption(t *testing.T) {
+	titled := []Video{{ID: "1", Title: "Kirish", Duration: 90}, {ID: "2"}, {ID: "3", Title: "Kombo"}}
+	untitled := []Video{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+
+	tests := []struct {
+		name       string
+		videos     []Video
+		start, end int
+		heading    string
+		want       string
+	}{
+		{"butun albom", untitled, 0, 3, "Chichi\n", "Chichi"},
+		{"bo'lak", untitled, 1, 3, "", "🎬 2-3/3"},
+		{"nomlangan videolar", titled, 0, 3, "Chichi", "Chichi\n\n1. Kirish (1:30)\n2. Video\n3. Kombo"},
+		{"nomlangan bo'lak", titled, 2, 3, "", "🎬 3-3/3\n\n3. Kombo"},
+		{"nomsiz bo'lak", titled, 1, 2, "Chichi", "Chichi\n\n🎬 2-2/3"},
+	}
+	for _, tt := range tests {
+		if got := albumCaption(tt.videos, tt.start, tt.end, tt.heading); got != tt.want {
+			t.Errorf("%s: albumCaption = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Albomdagi videolar soni (sendMediaGroup so'rovidagi media ro'yxati)
+func albumSize(t *testing.T, request fakeRequest) int {
+	t.Helper()
+	var media []json.RawMessage
+	if err := json.Unmarshal([]byte(request.Form.Get("media")), &media); err != nil {
+		t.Fatalf("media: %v", err)
+	}
+	return len(media)
+}
+
+// Fayl ID si saqlangan ketma-ket videolar 10 tadan albom bo'lib, qolganlari
+// alohida yuboriladi
+func TestSendEntryVideos(t *testing.T) {
+	cached := func(id string) Video { return Video{ID: id, FileID: "f" + id} }
+
+	tests := []struct {
+		name        string
+		videos      []Video
+		separate    bool
+		rejectAlbum bool
+		want        []string // so'rovlar: albom uchun "album:<soni>"
+	}{
+		{
+			name:   "12 ta video",
+			videos: []Video{cached("1"), cached("2"), cached("3"), cached("4"), cached("5"), cached("6"), cached("7"), cached("8"), cached("9"), cached("10"), cached("11"), cached("12")},
+			want:   []string{"album:10", "album:2"},
+		},
+		{
+			name:   "aralash",
+			videos: []Video{cached("1"), cached("2"), {ID: "3"}, cached("4")},
+			want:   []string{"album:2", "copyMessage", "sendVideo"},
+		},
+		{
+			name:     "alohida",
+			videos:   []Video{cached("1"), cached("2")},
+			separate: true,
+			want:     []string{"sendVideo", "sendVideo"},
+		},
+		{
+			name:        "albom rad etildi",
+			videos:      []Video{cached("1"), cached("2")},
+			rejectAlbum: true,
+			want:        []string{"album:2", "sendVideo", "sendVideo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, "guest")
+			if tt.rejectAlbum {
+				c.fake.respond = func(method string, form url.Values) string {
+					if method == "sendMediaGroup" {
+						return `{"ok":false,"error_code":400,"description":"Bad Request: wrong file identifier"}`
+					}
+					return ""
+				}
+			}
+			entry := testEntry("aaaa0001", "Fighter")
+			entry.Videos, entry.Separate = tt.videos, tt.separate
+			store.SaveEntry("tutorials", "Chichi", entry)
+
+			var got []string
+			var captions []string
+			for _, request := range c.click("entry_videos:aaaa0001") {
+				switch request.Method {
+				case "sendMediaGroup":
+					got = append(got, fmt.Sprintf("album:%d", albumSize(t, request)))
+					captions = append(captions, request.Form.Get("media"))
+				case "sendVideo", "copyMessage":
+					got = append(got, request.Method)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("so'rovlar = %v, want %v", got, tt.want)
+			}
+			// Yozuv ma'lumoti faqat birinchi albomda, bo'laklar raqamlanadi
+			if len(captions) == 2 && !tt.rejectAlbum {
+				if !strings.Contains(captions[0], "Chichi") || !strings.Contains(captions[0], "1-10/12") {
+					t.Errorf("birinchi albom: %s", captions[0])
+				}
+				if strings.Contains(captions[1], "Chichi") || !strings.Contains(captions[1], "11-12/12") {
+					t.Errorf("ikkinchi albom: %s", captions[1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Usta (wizard) tugmalari
+const (
+	wizardBackButton    = "⬅️ Oldingi qadam"
+	wizardSkipButton    = "⏭ O'tkazib yuborish"
+	wizardCancelButton  = "❌ Bekor qilish"
+	wizardConfirmButton = "✅ Saqlash"
+)
+
+// Qadam uchun standart kutish vaqti
+const wizardStepTimeout = 15 * time.Minute
+
+// Ustaning TempData dagi xizmat kalitlari
+const (
+	wizardStepKey   = "wizard_step"
+	wizardStepAtKey = "wizard_step_at"
+)
+
+// wizardStep - ustaning bitta qadami
+type wizardStep struct {
+	key    string // qiymat TempData ning shu kalitida saqlanadi
+	label  string // xulosada ko'rsatiladigan nom
+	prompt func(data map[string]string) string
+	// choices bo'lsa, faqat shu qiymatlardan birini tanlash mumkin
+	choices  func(data map[string]string) []string
+	optional bool // "O'tkazib yuborish" mumkin
+	// validate qiymatni tekshiradi va kerak bo'lsa normallashtiradi.
+	// Xatolik matni foydalanuvchiga ko'rsatiladi.
+	validate func(value string) (string, error)
+	// message bo'lsa, qiymat matn o'rniga butun xabardan olinadi
+	// (masalan, kanaldan forward qilingan video)
+	message func(data map[string]string, msg *tgbotapi.Message) (string, error)
+	// multiple qadamda bir nechta qiymat vergul bilan yig'iladi,
+	// keyingi qadamga "✔️ Tayyor" bosilganda o'tiladi
+	multiple bool
+	// format saqlangan qiymatni foydalanuvchiga ko'rsatish uchun (masalan, ID o'rniga nom)
+	format func(value string) string
+	// auto oldinga yurishda qadamni avtomatik to'ldiradi (masalan, oldin tanlangan rol)
+	auto    func(data map[string]string) (string, bool)
+	timeout time.Duration // 0 bo'lsa wizardStepTimeout
+}
+
+// wizard - ketma-ket qadamlardan iborat jarayon. Oxirgi qadamdan keyin
+// kiritilgan ma'lumotlar xulosasi ko'rsatilib, tasdiqlash so'raladi.
+type wizard struct {
+	name    string
+	heading string // xulosa sarlavhasi
+	steps   []wizardStep
+	// finish tasdiqlangandan keyin ma'lumotlarni saqlaydi. Xatolik bo'lsa
+	// foydalanuvchi tasdiqlash qadamida qoladi va qayta urinishi mumkin.
+	finish func(ctx *Context, data map[string]string) error
+}
+
+// Ro'yxatdan o'tgan ustalar (holat nomi bo'yicha)
+var wizards = make(map[string]*wizard)
+
+// Ustani routerga holat handleri sifatida qo'shish
+func (r *router) Wizard(w *wizard, options ...routeOption) {
+	wizards[w.state()] = w
+	r.State(w.state(), w.handle, options...)
+}
+
+// Holat nomi bo'yicha ustani topish
+func wizardFor(state string) (*wizard, bool) {
+	w, exists := wizards[state]
+	return w, exists
+}
+
+func (w *wizard) state() string {
+	return "wizard:" + w.name
+}
+
+// Ustani boshidan boshlash
+func (w *wizard) start(ctx *Context) {
+	for _, step := range w.steps {
+		delete(ctx.State.TempData, step.key)
+	}
+	ctx.State.State = w.state()
+	w.enter(ctx, 0, true)
+}
+
+// Joriy qadam raqami (len(steps) - tasdiqlash qadami)
+func (w *wizard) currentStep(state *UserState) int {
+	index, err := strconv.Atoi(state.TempData[wizardStepKey])
+	if err != nil || index < 0 || index > len(w.steps) {
+		return 0
+	}
+	return index
+}
+
+// Qadamga o'tish. Oldinga yurilganda avtomatik qadamlar to'ldirilib o'tkaziladi.
+func (w *wizard) enter(ctx *Context, index int, forward bool) {
+	for forward && index < len(w.steps) && w.steps[index].auto != nil {
+		value, ok := w.steps[index].auto(ctx.State.TempData)
+		if !ok {
+			break
+		}
+		ctx.State.TempData[w.steps[index].key] = value
+		sendMessage(ctx.Bot, ctx.ChatID, fmt.Sprintf("%s avtomatik tanlandi: %s", w.steps[index].label, w.steps[index].display(value)))
+		index++
+	}
+
+	ctx.State.TempData[wizardStepKey] = strconv.Itoa(index)
+	ctx.State.TempData[wizardStepAtKey] = strconv.FormatInt(time.Now().Unix(), 10)
+	w.sendPrompt(ctx.ChatID, ctx.State)
+}
+
+// Joriy qadam so'rovini klaviatura bilan yuborish
+func (w *wizard) sendPrompt(chatID int64, state *UserState) {
+	msg := tgbotapi.NewMessage(chatID, w.prompt(state))
+	msg.ReplyMarkup = w.keyboard(state)
+	outbox.Send(msg)
+}
+
+// Joriy qadam so'rovi matni
+func (w *wizard) prompt(state *UserState) string {
+	index := w.currentStep(state)
+	if index == len(w.steps) {
+		return w.summary(state.TempData)
+	}
+	return w.steps[index].prompt(state.TempData)
+}
+
+// Kiritilgan ma'lumotlar xulosasi
+func (w *wizard) summary(data map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", w.heading)
+	for _, step := range w.steps {
+		value := step.display(data[step.key])
+		if value == "" {
+			value = "—"
+		}
+		fmt.Fprintf(&b, "• %s: %s\n", step.label, value)
+	}
+	b.WriteString("\nHammasi to'g'rimi? Saqlash uchun \"" + wizardConfirmButton + "\" tugmasini bosing.")
+	return b.String()
+}
+
+// Joriy qadam klaviaturasi: tanlovlar, navigatsiya va bekor qilish tugmalari
+func (w *wizard) keyboard(state *UserState) tgbotapi.ReplyKeyboardMarkup {
+	index := w.currentStep(state)
+	var rows [][]tgbotapi.KeyboardButton
+
+	if index == len(w.steps) {
+		rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(wizardConfirmButton)))
+	} else if w.steps[index].choices != nil {
+		choices := w.steps[index].choices(state.TempData)
+		for i := 0; i < len(choices); i += 2 {
+			row := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(choices[i]))
+			if i+1 < len(choices) {
+				row = append(row, tgbotapi.NewKeyboardButton(choices[i+1]))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	var navigation []tgbotapi.KeyboardButton
+	if index > 0 {
+		navigation = append(navigation, tgbotapi.NewKeyboardButton(wizardBackButton))
+	}
+	if index < len(w.steps) && w.steps[index].optional {
+		navigation = append(navigation, tgbotapi.NewKeyboardButton(wizardSkipButton))
+	}
+	if index < len(w.steps) && w.steps[index].multiple && state.TempData[w.steps[index].key] != "" {
+		navigation = append(navigation, tgbotapi.NewKeyboardButton(doneButton))
+	}
+	if len(navigation) > 0 {
+		rows = append(rows, navigation)
+	}
+	rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(wizardCancelButton)))
+
+	keyboard := tgbotapi.NewReplyKeyboard(rows...)
+	keyboard.ResizeKeyboard = true
+	return keyboard
+}
+
+// Joriy qadam uchun ajratilgan vaqt tugaganmi
+func (w *wizard) stepExpired(state *UserState, index int) bool {
+	startedAt, err := strconv.ParseInt(state.TempData[wizardStepAtKey], 10, 64)
+	if err != nil {
+		return false
+	}
+	timeout := wizardStepTimeout
+	if index < len(w.steps) && w.steps[index].timeout > 0 {
+		timeout = w.steps[index].timeout
+	}
+	return time.Since(time.Unix(startedAt, 0)) > timeout
+}
+
+// Qadam vaqtini yangilash (masalan, bot qayta ishga tushganda)
+func (w *wizard) touch(state *UserState) {
+	state.TempData[wizardStepAtKey] = strconv.FormatInt(time.Now().Unix(), 10)
+}
+
+// Ustadagi foydalanuvchi xabarini qayta ishlash
+func (w *wizard) handle(ctx *Context) {
+	index := w.currentStep(ctx.State)
+
+	if w.stepExpired(ctx.State, index) {
+		resetUserState(ctx.User.ID)
+		sendMessage(ctx.Bot, ctx.ChatID, "⌛ Qadam uchun ajratilgan vaqt tugadi, jarayon bekor qilindi. Iltimos, qaytadan boshlang.")
+		sendMenu(ctx)
+		return
+	}
+
+	switch ctx.Text {
+	case wizardBackButton:
+		if index == 0 {
+			sendMessage(ctx.Bot, ctx.ChatID, "Bu birinchi qadam.")
+			w.sendPrompt(ctx.ChatID, ctx.State)
+			return
+		}
+		w.enter(ctx, index-1, false)
+		return
+
+	case wizardSkipButton:
+		if index < len(w.steps) && w.steps[index].optional {
+			ctx.State.TempData[w.steps[index].key] = ""
+			w.enter(ctx, index+1, true)
+			return
+		}
+		sendMessage(ctx.Bot, ctx.ChatID, "Bu qadamni o'tkazib yuborib bo'lmaydi.")
+		return
+
+	case doneButton:
+		if index < len(w.steps) && w.steps[index].multiple {
+			if ctx.State.TempData[w.steps[index].key] == "" {
+				sendMessage(ctx.Bot, ctx.ChatID, "Kamida bitta qiymat kiriting.")
+				return
+			}
+			w.enter(ctx, index+1, true)
+			return
+		}
+	}
+
+	// Tasdiqlash qadami
+	if index == len(w.steps) {
+		if ctx.Text != wizardConfirmButton {
+			w.sendPrompt(ctx.ChatID, ctx.State)
+			return
+		}
+		if err := w.finish(ctx, ctx.State.TempData); err != nil {
+			reportStoreError(ctx.Bot, ctx.ChatID, err)
+			return
+		}
+		resetUserState(ctx.User.ID)
+		return
+	}
+
+	step := w.steps[index]
+	var value string
+	var err error
+	if step.message != nil && ctx.Message != nil {
+		value, err = step.message(ctx.State.TempData, ctx.Message)
+	} else {
+		value, err = step.check(ctx.State.TempData, strings.TrimSpace(ctx.Text))
+	}
+	if err != nil {
+		sendMessage(ctx.Bot, ctx.ChatID, err.Error())
+		return
+	}
+
+	logUserAction(ctx.User, fmt.Sprintf("Admin: %s kiritildi", step.label), step.display(value))
+	if step.multiple {
+		values := append(splitList(ctx.State.TempData[step.key]), value)
+		ctx.State.TempData[step.key] = strings.Join(values, ",")
+		w.touch(ctx.State)
+		msg := tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("✅ Qabul qilindi (jami %d ta). Yana yuboring yoki \"%s\" tugmasini bosing.", len(values), doneButton))
+		msg.ReplyMarkup = w.keyboard(ctx.State)
+		outbox.Send(msg)
+		return
+	}
+	ctx.State.TempData[step.key] = value
+	w.enter(ctx, index+1, true)
+}
+
+// Vergul bilan saqlangan qiymatlar ro'yxati (bo'sh bo'lsa nil)
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// Qiymatni qadam talablariga ko'ra tekshirish
+func (step wizardStep) check(data map[string]string, value string) (string, error) {
+	if value == "" {
+		return "", errors.New("Iltimos, matn ko'rinishida qiymat kiriting.")
+	}
+	if step.choices != nil {
+		valid := false
+		for _, choice := range step.choices(data) {
+			if value == choice {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return "", errors.New("Noto'g'ri tanlov. Iltimos, taqdim etilgan tugmalardan birini tanlang.")
+		}
+	}
+	if step.validate != nil {
+		return step.validate(value)
+	}
+	return value, nil
+}
+
+// Qiymatni foydalanuvchiga ko'rsatiladigan ko'rinishga keltirish
+func (step wizardStep) display(value string) string {
+	if step.format != nil && value != "" {
+		return step.format(value)
+	}
+	return value
+}
+
+// Har qanday jarayonni bekor qilish (/cancel yoki "❌ Bekor qilish")
+func handleCancel(ctx *Context) {
+	if ctx.State.State == STATE_NONE || ctx.State.State == "" {
+		sendMessage(ctx.Bot, ctx.ChatID, "Bekor qilinadigan jarayon yo'q.")
+		sendMenu(ctx)
+		return
+	}
+	logUserAction(ctx.User, "Jarayon bekor qilindi", ctx.State.State)
+	resetUserState(ctx.User.ID)
+	sendMessage(ctx.Bot, ctx.ChatID, "Jarayon bekor qilindi.")
+	sendMenu(ctx)
+}
+
+// Foydalanuvchiga mos asosiy menyuni yuborish
+func sendMenu(ctx *Context) {
+	if isAdmin(ctx.User.UserName) {
+		sendAdminMenu(ctx.Bot, ctx.ChatID)
+	} else {
+		sendMainMenu(ctx.Bot, ctx.ChatID)
+	}
+}
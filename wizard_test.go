@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Sinov ustasi: majburiy nom, ixtiyoriy izoh va tanlovli rang
+func testWizard(finished *map[string]string) *wizard {
+	return &wizard{
+		name:    "test",
+		heading: "Sinov",
+		steps: []wizardStep{
+			{
+				key: "name", label: "Nom",
+				prompt: func(data map[string]string) string { return "Nomni kiriting:" },
+				validate: func(value string) (string, error) {
+					if len(value) > 10 {
+						return "", errors.New("Nom juda uzun.")
+					}
+					return value, nil
+				},
+			},
+			{
+				key: "note", label: "Izoh", optional: true,
+				prompt: func(data map[string]string) string { return "Izoh kiriting:" },
+			},
+			{
+				key: "color", label: "Rang",
+				prompt:  func(data map[string]string) string { return "Rangni tanlang:" },
+				choices: func(data map[string]string) []string { return []string{"Qizil", "Yashil"} },
+			},
+		},
+		finish: func(ctx *Context, data map[string]string) error {
+			*finished = make(map[string]string)
+			for _, key := range []string{"name", "note", "color"} {
+				(*finished)[key] = data[key]
+			}
+			return nil
+		},
+	}
+}
+
+func TestWizard(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputs   []string
+		expired  bool // oxirgi kiritishdan oldin qadam vaqti tugagan
+		wantStep int  // -1: usta tugagan yoki bekor qilingan
+		wantData map[string]string
+		wantText string // oxirgi javoblardan birida bo'lishi kerak
+	}{
+		{
+			name:     "to'liq jarayon",
+			inputs:   []string{"Ali", wizardSkipButton, "Qizil", wizardConfirmButton},
+			wantStep: -1,
+			wantData: map[string]string{"name": "Ali", "note": "", "color": "Qizil"},
+		},
+		{
+			name:     "tasdiqlashdan oldin xulosa",
+			inputs:   []string{"Ali", "izoh", "Yashil"},
+			wantStep: 3,
+			wantText: "• Izoh: izoh",
+		},
+		{
+			name:     "oldingi qadamga qaytish",
+			inputs:   []string{"Ali", "izoh", wizardBackButton},
+			wantStep: 1,
+			wantText: "Izoh kiriting:",
+		},
+		{
+			name:     "birinchi qadamdan orqaga yo'l yo'q",
+			inputs:   []string{wizardBackButton},
+			wantStep: 0,
+			wantText: "Bu birinchi qadam.",
+		},
+		{
+			name:     "majburiy qadamni o'tkazib bo'lmaydi",
+			inputs:   []string{wizardSkipButton},
+			wantStep: 0,
+			wantText: "o'tkazib yuborib bo'lmaydi",
+		},
+		{
+			name:     "tekshiruvdan o'tmagan qiymat",
+			inputs:   []string{"juda uzun nom"},
+			wantStep: 0,
+			wantText: "Nom juda uzun.",
+		},
+		{
+			name:     "noto'g'ri tanlov",
+			inputs:   []string{"Ali", wizardSkipButton, "Ko'k"},
+			wantStep: 2,
+			wantText: "Noto'g'ri tanlov",
+		},
+		{
+			name:     "qadam vaqti tugagan",
+			inputs:   []string{"Ali", "izoh"},
+			expired:  true,
+			wantStep: -1,
+			wantText: "vaqt tugadi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, fake := setupTestBot(t)
+			var finished map[string]string
+			w := testWizard(&finished)
+			user := &tgbotapi.User{ID: 42, UserName: "boss"}
+			newContext := func(text string) *Context {
+				return &Context{Bot: bot, User: user, ChatID: 42, State: getUserState(42), Text: text,
+					Message: &tgbotapi.Message{Text: text}}
+			}
+
+			w.start(newContext(""))
+			for i, input := range tt.inputs {
+				if tt.expired && i == len(tt.inputs)-1 {
+					startedAt := time.Now().Add(-wizardStepTimeout - time.Minute).Unix()
+					getUserState(42).TempData[wizardStepAtKey] = strconv.FormatInt(startedAt, 10)
+				}
+				fake.take()
+				w.handle(newContext(input))
+			}
+
+			state := getUserState(42)
+			step := -1
+			if state.State == w.state() {
+				step = w.currentStep(state)
+			}
+			if step != tt.wantStep {
+				t.Errorf("qadam = %d, want %d (holat %q)", step, tt.wantStep, state.State)
+			}
+			if tt.wantData != nil {
+				for key, want := range tt.wantData {
+					if finished[key] != want {
+						t.Errorf("finish[%s] = %q, want %q", key, finished[key], want)
+					}
+				}
+			} else if finished != nil {
+				t.Errorf("finish kutilmaganda chaqirildi: %v", finished)
+			}
+			if tt.wantText != "" {
+				var texts []string
+				for _, request := range fake.take() {
+					texts = append(texts, request.Form.Get("text"))
+				}
+				if !strings.Contains(strings.Join(texts, "\n"), tt.wantText) {
+					t.Errorf("javoblar %q, want %q", texts, tt.wantText)
+				}
+			}
+		})
+	}
+}
+
+func TestWizardKeyboard(t *testing.T) {
+	w := testWizard(new(map[string]string))
+	tests := []struct {
+		step int
+		want []string
+	}{
+		{0, []string{wizardCancelButton}},
+		{1, []string{wizardBackButton, wizardSkipButton, wizardCancelButton}},
+		{2, []string{"Qizil", "Yashil", wizardBackButton, wizardCancelButton}},
+		{3, []string{wizardConfirmButton, wizardBackButton, wizardCancelButton}},
+	}
+	for _, tt := range tests {
+		state := &UserState{TempData: map[string]string{wizardStepKey: strconv.Itoa(tt.step)}}
+		var got []string
+		for _, row := range w.keyboard(state).Keyboard {
+			for _, button := range row {
+				got = append(got, button.Text)
+			}
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%d-qadam tugmalari = %v, want %v", tt.step, got, tt.want)
+		}
+	}
+}